@@ -10,20 +10,36 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-
-	"github.com/lucasb-eyer/go-colorful"
 )
 
+// batchSize is the number of rules simulated together by each worker
+const batchSize = 16
+
+// symmetricScore is the minimum symmetry score to report a rule as symmetric
+const symmetricScore = 0.99
+
 func main() {
 	power := 12.0
-	works := make(chan string)
-	go func(works chan string) {
+	works := make(chan []string)
+	go func(works chan []string) {
+		batch := make([]string, 0, batchSize)
 		for i := 1.0; i <= power; i++ {
 			c := Combinations(i)
 			for i := range c {
-				works <- c[i]
+				// equivalent rules draw the same picture, only the canonical one is computed
+				if !langton.IsCanonical(langton.StepsFromString(c[i])) {
+					continue
+				}
+				batch = append(batch, c[i])
+				if len(batch) == batchSize {
+					works <- batch
+					batch = make([]string, 0, batchSize)
+				}
 			}
 		}
+		if len(batch) != 0 {
+			works <- batch
+		}
 		close(works)
 	}(works)
 
@@ -39,7 +55,7 @@ func main() {
 					log.Printf("closed %d", id)
 					break
 				}
-				log.Printf("Worker %d on %s\n", id, s)
+				log.Printf("Worker %d on %s\n", id, strings.Join(s, ", "))
 				Calculate(s)
 			}
 		}(i)
@@ -66,17 +82,36 @@ func Combinations(power float64) []string {
 	return out
 }
 
-func Calculate(steps string) {
-
-	ant := langton.NewAntFromString(
-		langton.NewBoard(1000),
-		steps,
-	)
-	_, err := ant.NextN(10000000)
+// Calculate simulates all the rules together and saves a picture for each one
+func Calculate(rules []string) {
+	steps := make([]langton.Steps, len(rules))
+	for i := range rules {
+		steps[i] = langton.StepsFromString(rules[i])
+	}
+	batch, err := langton.NewBatch(langton.NewBoard(1000), steps...)
 	if err != nil {
-		log.Printf("reached limit! %s\n", steps)
+		panic(err)
 	}
-	colorfulPalette, err := colorful.SoftPalette(len(steps))
+	batch.Run(10000000)
+
+	for i := range rules {
+		result := batch.Result(i)
+		if result.Stuck {
+			log.Printf("reached limit! %s\n", rules[i])
+		}
+		log.Printf("%s visited %s\n", rules[i], &result.Visited)
+		ant := batch.Ant(i)
+		if best := ant.Symmetries()[0]; best.Score >= symmetricScore {
+			log.Printf("%s is symmetric, %s\n", rules[i], best)
+		}
+		Save(ant, rules[i])
+	}
+}
+
+// Save writes the picture of the ant in the outs folder
+func Save(ant *langton.Ant, steps string) {
+	colorfulPalette, err := langton.SoftPalette(len(steps))
+	ant.Crop(1)
 	img := langton.ToImage(ant, langton.ToPalette(colorfulPalette), 1)
 	file, err := os.Create("outs/" + steps + ".png")
 	if err != nil {
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"go-ant/langton"
+	"go-ant/patterns"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+
+	var (
+		budget    int64
+		size      int64
+		maxPeriod int
+		verbose   bool
+	)
+
+	flag.Int64Var(&budget, "budget", langton.DefaultClassifierBudget, "steps run for each rule")
+	flag.Int64Var(&size, "size", langton.DefaultClassifierSize, "distance in cells from the center to the sides of the board")
+	flag.IntVar(&maxPeriod, "max-period", langton.DefaultMaxHighwayPeriod, "longest highway period searched")
+	flag.BoolVar(&verbose, "v", false, "print the measured features of each rule")
+	selection := patterns.Flags(flag.CommandLine)
+	flag.Parse()
+
+	classifier := &langton.Classifier{
+		Budget:    budget,
+		Size:      size,
+		MaxPeriod: maxPeriod,
+	}
+
+	rules := flag.Args()
+	if selection.Selected() {
+		entries, err := selection.Entries()
+		if err != nil {
+			panic(err)
+		}
+		for _, entry := range entries {
+			rules = append(rules, entry.Rule)
+		}
+	}
+	if len(rules) == 0 {
+		// rules are read from the input in the format of patterns.txt, comments are ignored
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			rule := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0])
+			if rule != "" {
+				rules = append(rules, rule)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, rule := range rules {
+		steps, err := langton.ParseSteps(rule)
+		if err != nil {
+			log.Printf("WARNING: skipping %s, %s", rule, err)
+			continue
+		}
+		tags, features, err := classifier.Classify(steps)
+		if err != nil {
+			log.Printf("WARNING: skipping %s, %s", rule, err)
+			continue
+		}
+		fmt.Printf("%s # %s\n", rule, strings.Join(tags, ", "))
+		if verbose {
+			fmt.Printf("# %+v\n", features)
+		}
+	}
+}
@@ -100,8 +100,8 @@ func (c *Camera) DrawAnt(ant *langton.Ant, screen *ebiten.Image, palette color.P
 			x = math.Floor(x)
 			y = math.Floor(y)
 
-			cell, err := ant.CellAt(langton.Point{int64(x), int64(y)})
-			if err != nil {
+			cell, ok := ant.Board.Cell(langton.Point{X: int64(x), Y: int64(y)})
+			if !ok {
 				continue
 			}
 			screen.Set(int(sx), int(sy), palette[cell.Step.Index+1])
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"flag"
 	"go-ant/langton"
+	"go-ant/patterns"
 	"image"
 	"image/color"
 	"log"
 	"math"
 	"os"
 	"runtime/pprof"
+	"strings"
 	"time"
 
 	"github.com/hajimehoshi/ebiten"
@@ -157,16 +159,13 @@ func (g *Game) Update(screen *ebiten.Image) error {
 		}
 
 		antGridSize := int64(3000)
-		g.ant = langton.NewAntFromString(
-			langton.NewBoard(antGridSize),
-			g.properties.sequence,
-		)
-
-		p, err := colorful.HappyPalette(len(g.properties.sequence))
+		ant, err := newAnt(g.properties.sequence, antGridSize)
 		if err != nil {
-			panic(err)
+			return err
 		}
-		g.palette = langton.ToPalette(p)
+		g.ant = ant
+
+		g.palette = newPalette(g.ant)
 	}
 
 	g.properties.antPendingSteps += g.properties.antStepsPerSeccond * delta
@@ -206,6 +205,7 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.printer.Sprintf(
 			`TPS: %0.2f - FPS: %0.2f
 Cell %s
+Cells per colour %v
 Now playing "%s"
 Steps x Seccond %0.2f
 Total Steps %d,
@@ -216,6 +216,7 @@ Type sequence with LR and press Enter to play: "%s"`,
 			ebiten.CurrentTPS(),
 			ebiten.CurrentFPS(),
 			cell,
+			g.ant.Histogram(),
 			g.properties.sequence,
 			g.properties.antStepsPerSeccond,
 			g.ant.TotalSteps(),
@@ -250,6 +251,8 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 }
 
 var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
+var turmite = flag.String("turmite", "", "turmite in brace notation to start with. e.g. {{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+var selection = patterns.Flags(flag.CommandLine)
 
 func main() {
 
@@ -267,28 +270,61 @@ func main() {
 	ebiten.SetWindowResizable(true)
 	ebiten.SetRunnableOnUnfocused(true)
 
-	sequence := "LR"
+	sequence, err := selection.Rule("LR")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *turmite != "" {
+		sequence = *turmite
+	}
 	antGridSize := int64(1000)
-	ant := langton.NewAntFromString(
-		langton.NewBoard(antGridSize),
-		sequence,
-	)
-
-	p, err := colorful.HappyPalette(len(sequence))
+	ant, err := newAnt(sequence, antGridSize)
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
+
 	g := &Game{
 		camera: Camera{
 			ZoomFactor: 10,
 		},
 		ant:        ant,
-		palette:    langton.ToPalette(p),
+		palette:    newPalette(ant),
 		properties: defaultProperties(),
 
 		printer: message.NewPrinter(message.MatchLanguage("en")),
 	}
+	g.properties.sequence = sequence
 	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// newAnt creates an ant for a sequence of steps or for a turmite in brace notation
+func newAnt(sequence string, size int64) (*langton.Ant, error) {
+	if strings.HasPrefix(sequence, "{") {
+		turmite, err := langton.ParseTurmite(sequence)
+		if err != nil {
+			return nil, err
+		}
+		return langton.NewTurmiteAnt(langton.NewBoard(size), turmite)
+	}
+	steps, err := langton.ParseSteps(sequence)
+	if err != nil {
+		return nil, err
+	}
+	return langton.NewAnt(langton.NewBoard(size), steps...), nil
+}
+
+// newPalette returns a happy palette with a colour per ant step.
+// Happy palettes are too slow to generate for long rules, those get a gradient
+func newPalette(ant *langton.Ant) color.Palette {
+	colours := len(ant.Steps())
+	if colours > 256 {
+		return langton.ToPalette(langton.GradientPalette(colours))
+	}
+	p, err := colorful.HappyPalette(colours)
+	if err != nil {
+		panic(err)
+	}
+	return langton.ToPalette(p)
+}
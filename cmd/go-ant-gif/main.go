@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"go-ant/langton"
+	"go-ant/patterns"
 	"image"
 	"image/gif"
 	"io"
@@ -10,7 +11,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/lucasb-eyer/go-colorful"
 	"github.com/pkg/browser"
 
 	"github.com/schollz/progressbar/v3"
@@ -22,15 +22,24 @@ func main() {
 		frames          int
 		iterations      int
 		steps           string
+		schedule        string
 		outFile         string
 		pixelSize       int
 		area            int64
 		durationSeconds int
 		open            bool
 		lastFrameDelay  int
+		seed            int64
+		noiseEvery      int64
+		noiseRadius     int64
+		rleIn           string
+		turmite         string
+		rleOut          string
 	)
 
 	flag.StringVar(&steps, "steps", "LR", "Ant step sequence")
+	selection := patterns.Flags(flag.CommandLine)
+	flag.StringVar(&schedule, "schedule", "", "sequences to follow one after another as steps:duration, overrides steps, not for turmites. e.g. LR:10000,LLRR")
 	flag.StringVar(&outFile, "out", "out.gif", "output file")
 	flag.IntVar(&iterations, "iterations", 10927, "Total number of ant iterations")
 	flag.IntVar(&frames, "frames", 200, "total gif frames")
@@ -39,7 +48,17 @@ func main() {
 	flag.Int64Var(&area, "area", 70, "size in cells for the ant to walk")
 	flag.BoolVar(&open, "open", false, "open the output in a browser")
 	flag.IntVar(&lastFrameDelay, "last-frame-delay", 1000, "milliseconds for the last frame")
+	flag.Int64Var(&seed, "seed", 0, "seed for stochastic steps and noise")
+	flag.Int64Var(&noiseEvery, "noise-every", 0, "flip a random cell around the ant every n steps, 0 disables noise")
+	flag.Int64Var(&noiseRadius, "noise-radius", 5, "maximum distance from the ant to the flipped cells")
+	flag.StringVar(&rleIn, "rle", "", "Golly RLE pattern to start from, overrides steps")
+	flag.StringVar(&turmite, "turmite", "", "turmite in brace notation, overrides steps. e.g. {{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+	flag.StringVar(&rleOut, "out-rle", "", "write the final state as a Golly RLE pattern to this file")
 	flag.Parse()
+	steps, err := selection.Rule(steps)
+	if err != nil {
+		panic(err)
+	}
 
 	var (
 		duration           = time.Duration(durationSeconds) * time.Second
@@ -61,12 +80,36 @@ func main() {
 
 	log.Printf("INFO: frame rate %f, updates per frame %d", 100/float64(delayBetweenFrames), updatesPerFrame)
 
-	ant := langton.NewAntFromString(
-		langton.NewBoard(area/2),
-		steps,
-	)
+	ant, err := newAnt(steps, turmite, rleIn, area)
+	if err != nil {
+		panic(err)
+	}
+	antSteps := ant.Steps()
+	ant.Seed(seed)
+	if noiseEvery > 0 {
+		err = ant.SetNoise(langton.FlipNoise{
+			Every:  noiseEvery,
+			Radius: noiseRadius,
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	colors := len(antSteps)
+	if schedule != "" {
+		antSchedule, err := langton.ParseSchedule(schedule)
+		if err != nil {
+			panic(err)
+		}
+		err = ant.SetSchedule(antSchedule)
+		if err != nil {
+			panic(err)
+		}
+		colors = antSchedule.MaxColors()
+	}
 
-	colorfulPalette, err := colorful.SoftPalette(len(steps))
+	colorfulPalette, err := langton.SoftPalette(colors)
 	if err != nil {
 		panic(err)
 	}
@@ -115,11 +158,47 @@ func main() {
 		panic(err)
 	}
 
+	if rleOut != "" {
+		rleFile, err := os.Create(rleOut)
+		if err != nil {
+			panic(err)
+		}
+		defer rleFile.Close()
+		err = langton.WriteRLE(rleFile, ant)
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	if open {
 		browser.OpenFile(outFile)
 	}
 }
 
+// newAnt creates the ant from the steps or, if given, from a turmite or a Golly RLE pattern
+func newAnt(steps string, turmite string, rle string, area int64) (*langton.Ant, error) {
+	if turmite != "" {
+		antTurmite, err := langton.ParseTurmite(turmite)
+		if err != nil {
+			return nil, err
+		}
+		return langton.NewTurmiteAnt(langton.NewBoard(area/2), antTurmite)
+	}
+	if rle == "" {
+		antSteps, err := langton.ParseSteps(steps)
+		if err != nil {
+			return nil, err
+		}
+		return langton.NewAnt(langton.NewBoard(area/2), antSteps...), nil
+	}
+	file, err := os.Open(rle)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return langton.ReadRLE(file, area/2)
+}
+
 // GifFrameOptimizer turns repeated pixels to transparent to the final gif size is minimal.
 func GifFrameOptimizer() func(img *image.Paletted) {
 	var currentImage *image.Paletted
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"go-ant/graph"
+	"go-ant/langton"
+	"go-ant/patterns"
+	"image/png"
+	"log"
+	"os"
+)
+
+func main() {
+
+	var (
+		tiling      string
+		generations int
+		size        int
+		missing     float64
+		seed        int64
+		steps       string
+		iterations  int
+		scale       float64
+		outFile     string
+	)
+
+	flag.StringVar(&tiling, "tiling", "penrose", "board where the ant walks, penrose or grid")
+	flag.IntVar(&generations, "generations", 6, "subdivisions of the penrose tiling, each one multiplies the tiles by 2.6")
+	flag.IntVar(&size, "size", 100, "side of the grid in cells")
+	flag.Float64Var(&missing, "missing", 0, "probability of removing each edge of the grid")
+	flag.Int64Var(&seed, "seed", 0, "seed for the removed edges")
+	flag.StringVar(&steps, "steps", "LR", "Ant step sequence")
+	selection := patterns.Flags(flag.CommandLine)
+	flag.IntVar(&iterations, "iterations", 10000, "Total number of ant iterations")
+	flag.Float64Var(&scale, "scale", 10, "pixels per tile side")
+	flag.StringVar(&outFile, "out", "out.png", "output file")
+	flag.Parse()
+	steps, err := selection.Rule(steps)
+	if err != nil {
+		panic(err)
+	}
+
+	var (
+		board *graph.Graph
+		start graph.Vec
+	)
+	switch tiling {
+	case "penrose":
+		board = graph.NewPenrose(generations)
+	case "grid":
+		board = graph.NewGrid(size, size, missing, langton.NewRandom(seed))
+		start = graph.Vec{X: float64(size) / 2, Y: float64(size) / 2}
+	default:
+		log.Fatalf("unknown tiling %q", tiling)
+	}
+
+	antSteps, err := langton.ParseSteps(steps)
+	if err != nil {
+		panic(err)
+	}
+	ant, err := graph.NewAnt(board, board.Nearest(start), antSteps...)
+	if err != nil {
+		panic(err)
+	}
+	ant.NextN(iterations)
+
+	palette, err := langton.SoftPalette(len(antSteps))
+	if err != nil {
+		panic(err)
+	}
+	img := graph.ToImage(ant, langton.ToPalette(palette), scale)
+
+	file, err := os.Create(outFile)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	err = png.Encode(file, img)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go-ant/langton"
+	"go-ant/patterns"
+	"image/color"
+	"image/png"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// chunk is the number of steps computed between checkpoint checks
+const chunk = 1 << 16
+
+func main() {
+
+	var (
+		steps      string
+		turmite    string
+		board      string
+		area       int64
+		iterations int64
+		seed       int64
+		dir        string
+		every      int64
+		interval   time.Duration
+		keep       int
+		outFile    string
+		pixelSize  int
+		growthFile string
+		samples    int
+		view       string
+		width      int
+		height     int
+		reduction  string
+		marker     bool
+		grid       bool
+		legend     bool
+	)
+
+	flag.StringVar(&steps, "steps", "LR", "Ant step sequence")
+	selection := patterns.Flags(flag.CommandLine)
+	flag.StringVar(&turmite, "turmite", "", "turmite in brace notation, overrides steps")
+	flag.StringVar(&board, "board", "compact", "board type, dense, sparse or compact")
+	flag.Int64Var(&area, "area", 1000, "size in cells for the ant to walk")
+	flag.Int64Var(&iterations, "iterations", 1000000000, "Total number of ant iterations")
+	flag.Int64Var(&seed, "seed", 0, "seed for stochastic steps")
+	flag.StringVar(&dir, "checkpoint-dir", "checkpoints", "directory for the checkpoints, the run resumes from the newest one")
+	flag.Int64Var(&every, "checkpoint-every", 0, "write a checkpoint every n steps, 0 disables it")
+	flag.DurationVar(&interval, "checkpoint-interval", 5*time.Minute, "write a checkpoint every interval, 0 disables it")
+	flag.IntVar(&keep, "checkpoint-keep", langton.DefaultCheckpointKeep, "number of checkpoints to keep")
+	flag.StringVar(&outFile, "out", "out.png", "output file, png or svg if it ends with .svg")
+	flag.IntVar(&pixelSize, "pixel-size", 1, "size in pixels of each cell")
+	flag.StringVar(&view, "view", "", "area of the board drawn as minX,minY,maxX,maxY, the visited area if empty")
+	flag.IntVar(&width, "width", 0, "maximum width of the png in pixels keeping the aspect ratio, 0 uses pixel-size")
+	flag.IntVar(&height, "height", 0, "maximum height of the png in pixels keeping the aspect ratio, 0 uses pixel-size")
+	flag.StringVar(&reduction, "reduction", "majority", "colour of the pixels that cover several cells, majority, average or any")
+	flag.BoolVar(&marker, "marker", false, "draw the ant in the svg")
+	flag.BoolVar(&grid, "grid", false, "draw the lines between cells in the svg")
+	flag.BoolVar(&legend, "legend", false, "draw the colours of the rule under the svg")
+	flag.StringVar(&growthFile, "growth", "", "write the growth metrics to this file, json if it ends with .json and csv otherwise")
+	flag.IntVar(&samples, "growth-samples", 100, "number of growth samples, a resumed run only samples from the resumed step")
+	flag.Parse()
+	steps, err := selection.Rule(steps)
+	if err != nil {
+		panic(err)
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		panic(err)
+	}
+
+	antSteps, antTurmite, err := parseRule(steps, turmite)
+	if err != nil {
+		panic(err)
+	}
+	ant, err := langton.Resume(dir)
+	switch {
+	case err == nil:
+		log.Printf("INFO: resuming %s from step %d", ant.Steps(), ant.TotalSteps())
+		if ruleFlagSet() && !sameRule(ant, antSteps, antTurmite) {
+			log.Fatalf("the checkpoint in %s runs %s, not the requested rule, use another checkpoint-dir", dir, ruleString(ant))
+		}
+		warnIgnoredFlags(ant, board, area)
+	case errors.Is(err, langton.ErrNoCheckpoint):
+		ant, err = newAnt(antSteps, antTurmite, board, area)
+		if err != nil {
+			panic(err)
+		}
+		ant.Seed(seed)
+	default:
+		panic(err)
+	}
+
+	checkpointer := &langton.Checkpointer{
+		Dir:      dir,
+		Every:    every,
+		Interval: interval,
+		Keep:     keep,
+	}
+	checkpointer.Start(ant)
+
+	growth := langton.NewGrowth(ant)
+	sampleSteps := []int64{}
+	if growthFile != "" {
+		for _, step := range langton.GrowthSteps(iterations, samples) {
+			if step > ant.TotalSteps() {
+				sampleSteps = append(sampleSteps, step)
+			}
+		}
+	}
+
+	bar := progressbar.Default(iterations, "Calculating")
+	bar.Set64(ant.TotalSteps())
+	for ant.TotalSteps() < iterations && !ant.Stuck() {
+		n := iterations - ant.TotalSteps()
+		if n > chunk {
+			n = chunk
+		}
+		if len(sampleSteps) != 0 && sampleSteps[0]-ant.TotalSteps() < n {
+			n = sampleSteps[0] - ant.TotalSteps()
+		}
+		before := ant.TotalSteps()
+		_, err := ant.NextN(int(n))
+		bar.Add64(ant.TotalSteps() - before)
+		if err != nil {
+			log.Printf("Bound reached at step %d, increase the area", ant.TotalSteps())
+			break
+		}
+		if len(sampleSteps) != 0 && sampleSteps[0] == ant.TotalSteps() {
+			growth.Sample(ant)
+			sampleSteps = sampleSteps[1:]
+		}
+		_, err = checkpointer.Check(ant)
+		if err != nil {
+			log.Printf("WARNING: checkpoint failed, %s", err)
+		}
+	}
+	_, err = checkpointer.Save(ant)
+	if err != nil {
+		log.Printf("WARNING: checkpoint failed, %s", err)
+	}
+
+	if growthFile != "" {
+		growth.Fit(ant)
+		log.Printf("INFO: growth exponent %.3f, fractal dimension %.3f", growth.Exponent, growth.FractalDimension)
+		err = writeGrowth(growthFile, growth)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	colours, err := langton.SoftPalette(len(ant.Steps()))
+	if err != nil {
+		panic(err)
+	}
+	if strings.HasSuffix(outFile, ".svg") {
+		world, err := parseView(ant, view)
+		if err != nil {
+			panic(err)
+		}
+		svg := &langton.SVG{
+			World:    world,
+			CellSize: float64(pixelSize),
+			Marker:   marker,
+			Grid:     grid,
+			Legend:   legend,
+		}
+		err = writeSVG(outFile, svg, ant, langton.ToPalette(colours))
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
+	viewport, err := newViewport(ant, view, width, height, pixelSize, reduction)
+	if err != nil {
+		panic(err)
+	}
+	img, err := viewport.Render(ant, langton.ToPalette(colours))
+	if err != nil {
+		panic(err)
+	}
+	file, err := os.Create(outFile)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	err = png.Encode(file, img)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// writeGrowth writes the growth metrics as json or csv depending on the file extension
+func writeGrowth(path string, growth *langton.Growth) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if strings.HasSuffix(path, ".json") {
+		return growth.WriteJSON(file)
+	}
+	return growth.WriteCSV(file)
+}
+
+// writeSVG writes the board in the file
+func writeSVG(path string, svg *langton.SVG, ant *langton.Ant, palette color.Palette) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = svg.Write(file, ant, palette)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// parseView returns the area given as minX,minY,maxX,maxY, or the visited area if it is empty
+func parseView(ant *langton.Ant, view string) (langton.Dimensions, error) {
+	if view == "" {
+		return ant.Visited(), nil
+	}
+	var minX, minY, maxX, maxY int64
+	_, err := fmt.Sscanf(view, "%d,%d,%d,%d", &minX, &minY, &maxX, &maxY)
+	if err != nil {
+		return langton.Dimensions{}, fmt.Errorf("invalid view %q, %w", view, err)
+	}
+	return langton.NewDimensions(minX, minY, maxX, maxY), nil
+}
+
+// newViewport returns the viewport of the area given as minX,minY,maxX,maxY, or of the visited area if it is empty.
+// The image fits in width and height, if both are 0 each cell takes pixelSize pixels
+func newViewport(ant *langton.Ant, view string, width int, height int, pixelSize int, reduction string) (langton.Viewport, error) {
+	world, err := parseView(ant, view)
+	if err != nil {
+		return langton.Viewport{}, err
+	}
+	r, err := langton.ParseReduction(reduction)
+	if err != nil {
+		return langton.Viewport{}, err
+	}
+	if width == 0 && height == 0 {
+		return langton.Viewport{
+			World:     world,
+			Width:     int(world.Width()) * pixelSize,
+			Height:    int(world.Height()) * pixelSize,
+			Reduction: r,
+		}, nil
+	}
+	if width == 0 {
+		width = height
+	}
+	if height == 0 {
+		height = width
+	}
+	return langton.FitViewport(world, width, height, r), nil
+}
+
+// parseRule parses the steps or, if given, the turmite and its steps
+func parseRule(steps string, turmite string) (langton.Steps, *langton.Turmite, error) {
+	if turmite != "" {
+		antTurmite, err := langton.ParseTurmite(turmite)
+		if err != nil {
+			return nil, nil, err
+		}
+		return antTurmite.Turns(), antTurmite, nil
+	}
+	antSteps, err := langton.ParseSteps(steps)
+	return antSteps, nil, err
+}
+
+// ruleFlagSet returns true if any of the flags that choose the rule has been given
+func ruleFlagSet() bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "steps", "turmite", "pattern", "tag":
+			set = true
+		}
+	})
+	return set
+}
+
+// sameRule returns true if the ant follows the steps, or the turmite if it is not nil
+func sameRule(ant *langton.Ant, steps langton.Steps, turmite *langton.Turmite) bool {
+	if turmite != nil || ant.Turmite() != nil {
+		return turmite != nil && ant.Turmite() != nil && turmite.String() == ant.Turmite().String()
+	}
+	return ant.Steps().String() == steps.String()
+}
+
+// ruleString returns the turmite of the ant in brace notation or its steps
+func ruleString(ant *langton.Ant) string {
+	if ant.Turmite() != nil {
+		return ant.Turmite().String()
+	}
+	return ant.Steps().String()
+}
+
+// warnIgnoredFlags logs the flags given for a new ant that a resumed ant does not follow
+func warnIgnoredFlags(ant *langton.Ant, board string, area int64) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "board":
+			if kind := boardKind(ant.Board); kind != board {
+				log.Printf("WARNING: ignoring -board %s, the checkpoint uses a %s board", board, kind)
+			}
+		case "area":
+			if dimensions := ant.Dimensions(); dimensions != langton.NewBoard(area/2) {
+				log.Printf("WARNING: ignoring -area %d, the checkpoint board is %s", area, &dimensions)
+			}
+		case "seed":
+			log.Printf("WARNING: ignoring -seed, the checkpoint keeps the random state of the ant")
+		}
+	})
+}
+
+// boardKind returns the name of the board type used by the -board flag
+func boardKind(board langton.Board) string {
+	switch board.(type) {
+	case *langton.DenseBoard:
+		return "dense"
+	case *langton.SparseBoard:
+		return "sparse"
+	case *langton.CompactBoard:
+		return "compact"
+	default:
+		return fmt.Sprintf("%T", board)
+	}
+}
+
+// newAnt creates the ant on the given board type, following the turmite if it is not nil
+func newAnt(steps langton.Steps, turmite *langton.Turmite, board string, area int64) (*langton.Ant, error) {
+	dimensions := langton.NewBoard(area / 2)
+	var antBoard langton.Board
+	switch board {
+	case "dense":
+		antBoard = langton.NewDenseBoard(dimensions)
+	case "sparse":
+		antBoard = langton.NewSparseBoard(dimensions)
+	case "compact":
+		if len(steps) > langton.MaxCompactSteps {
+			return nil, errors.New("too many steps for a compact board")
+		}
+		antBoard = langton.NewCompactBoard(dimensions, steps)
+	default:
+		return nil, errors.New("unknown board " + board)
+	}
+	ant := langton.NewAntOnBoard(antBoard, steps...)
+	if turmite != nil {
+		return ant, ant.SetTurmite(turmite)
+	}
+	return ant, nil
+}
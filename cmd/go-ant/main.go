@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"go-ant/langton"
+	"go-ant/patterns"
 	"image/png"
 	"io/ioutil"
 	"log"
@@ -27,9 +28,11 @@ import (
 )
 
 var steps string
+var turmite string
 var antSpeed int64
 var gridSize int64
 var pixelSize int
+var selection = patterns.Flags(flag.CommandLine)
 
 func run() {
 	cfg := pixelgl.WindowConfig{
@@ -42,14 +45,24 @@ func run() {
 		panic(err)
 	}
 
-	palette, err := colorful.SoftPalette(len(steps))
+	liveAnt := langton.NewAntFromString(langton.NewBoard(gridSize/2), steps)
+	if turmite != "" {
+		antTurmite, err := langton.ParseTurmite(turmite)
+		if err != nil {
+			panic(err)
+		}
+		liveAnt, err = langton.NewTurmiteAnt(langton.NewBoard(gridSize/2), antTurmite)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	palette, err := langton.SoftPalette(len(liveAnt.Steps()))
 	if err != nil {
 		panic(err)
 	}
 
-	ant := langton.NewAntFromString(
-		langton.NewBoard(gridSize/2),
-		steps)
+	ant := langton.NewSyncAnt(liveAnt)
 
 	var (
 		camPos                  = pixel.ZV
@@ -85,7 +98,7 @@ func run() {
 		}
 	}()
 
-	loadLastPic := LastPic(ant, palette)
+	loadLastPic := LastPic(ant.NewView(), palette)
 	nextPic := make(chan *pixel.Sprite)
 	lastPic := loadLastPic()
 
@@ -132,8 +145,10 @@ func run() {
 			camPos.Y += camSpeed * dt / camZoom
 		}
 		if win.JustPressed(pixelgl.KeyS) {
+			view := ant.NewView()
 			go func() {
-				image := langton.ToImage(ant, langton.ToPalette(palette), pixelSize)
+				image := langton.ToImage(view.Update(), langton.ToPalette(palette), pixelSize)
+				view.Close()
 
 				buf, err := ioutil.TempFile("", "pic")
 				if err != nil {
@@ -206,10 +221,11 @@ func run() {
 			lastPic.Draw(win, pixel.IM)
 		}
 
+		dimensions := ant.Dimensions()
 		imd.Clear()
 		imd.Color = colornames.Red
-		imd.Push(pixel.V(float64(ant.Dimensions.BottomLeft.X-1), float64(ant.Dimensions.BottomLeft.Y-1)).Scaled(float64(pixelSize)))
-		imd.Push(pixel.V(float64(ant.Dimensions.TopRight.X+1), float64(ant.Dimensions.TopRight.Y+1)).Scaled(float64(pixelSize)))
+		imd.Push(pixel.V(float64(dimensions.BottomLeft.X-1), float64(dimensions.BottomLeft.Y-1)).Scaled(float64(pixelSize)))
+		imd.Push(pixel.V(float64(dimensions.TopRight.X+1), float64(dimensions.TopRight.Y+1)).Scaled(float64(pixelSize)))
 		imd.Rectangle(2)
 		imd.Draw(win)
 
@@ -219,7 +235,7 @@ func run() {
 		p := message.NewPrinter(language.Spanish)
 
 		p.Fprintf(basicTxt, "Steps %s\n", steps)
-		p.Fprintf(basicTxt, "Grid Size: %s\n", ant.Dimensions)
+		p.Fprintf(basicTxt, "Grid Size: %s\n", &dimensions)
 		p.Fprintf(basicTxt, "Delay between steps: %s\n", time.Duration(antSpeed))
 		p.Fprintf(basicTxt, "Real Steps Per Seccond: %d\n", atomic.LoadUint64(&antRealSpeed))
 		p.Fprintf(basicTxt, "Total Steps: %d\n", ant.TotalSteps())
@@ -246,16 +262,23 @@ func run() {
 
 func main() {
 	flag.StringVar(&steps, "steps", "RLLLLRRRLLL", "Provide the sequence as L for left and R for right")
+	flag.StringVar(&turmite, "turmite", "", "turmite in brace notation, overrides steps. e.g. {{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
 	flag.Int64Var(&antSpeed, "speed", 10000, "the number of nanoseconds to want between interactions. 0 for no wait")
 	flag.Int64Var(&gridSize, "size", 100, "Image width_x_height dimensions, Equivalent to grid size")
 	flag.IntVar(&pixelSize, "pixel-size", 10, "determines the final image size by multiplying this value by the area")
 	flag.Parse()
+	rule, err := selection.Rule(steps)
+	if err != nil {
+		panic(err)
+	}
+	steps = rule
 
 	pixelgl.Run(run)
 
 }
 
-func LastPic(ant *langton.Ant, palette []colorful.Color) func() *pixel.Sprite {
+func LastPic(view *langton.View, palette []colorful.Color) func() *pixel.Sprite {
+	ant := view.Update()
 	steps := ant.TotalSteps()
 	var (
 		sprite *pixel.Sprite
@@ -265,6 +288,7 @@ func LastPic(ant *langton.Ant, palette []colorful.Color) func() *pixel.Sprite {
 	sprite = pixel.NewSprite(pic, pic.Bounds())
 
 	return func() *pixel.Sprite {
+		ant := view.Update()
 		if ant.TotalSteps() == steps {
 			return sprite
 		}
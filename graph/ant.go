@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"fmt"
+	"go-ant/langton"
+)
+
+// Ant follows a langton rule on a Graph.
+// Turning left takes the next edge counter-clockwise, turning right the previous one.
+// When the chosen edge is missing the ant bounces, it stays in the node and faces the way it came from
+type Ant struct {
+	Graph *Graph
+	// Node is the index of the node where the ant is
+	Node int
+	// Heading is the index of the edge of Node the ant is facing
+	Heading int
+
+	steps langton.Steps
+	// colours holds the step index + 1 of every node, 0 if it has never been visited
+	colours    []int
+	totalSteps int64
+}
+
+// NewAnt creates an ant in the start node facing its first edge.
+// The graph must be valid, a node without edges would leave the ant nowhere to turn.
+// Stochastic steps are not supported
+func NewAnt(graph *Graph, start int, steps ...langton.Step) (*Ant, error) {
+	if start < 0 || start >= len(graph.Nodes) {
+		return nil, fmt.Errorf("%w: start node %d does not exist", ErrInvalidGraph, start)
+	}
+	err := graph.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%w: empty sequence", langton.ErrInvalidSteps)
+	}
+	for _, step := range steps {
+		if step.Stochastic() {
+			return nil, fmt.Errorf("%w: stochastic steps are not supported on graphs", langton.ErrInvalidSteps)
+		}
+	}
+	rule := make(langton.Steps, len(steps))
+	copy(rule, steps)
+	rule.Numerate()
+
+	ant := &Ant{
+		Graph:   graph,
+		Node:    start,
+		steps:   rule,
+		colours: make([]int, len(graph.Nodes)),
+	}
+	ant.colours[start] = 1
+	return ant, nil
+}
+
+// Steps returns the sequence of steps followed by the ant
+func (ant *Ant) Steps() langton.Steps {
+	return ant.steps
+}
+
+// TotalSteps returns the total steps performed by the ant
+func (ant *Ant) TotalSteps() int64 {
+	return ant.totalSteps
+}
+
+// Colour returns the step index of the node, ok is false if it has never been visited
+func (ant *Ant) Colour(node int) (index int, ok bool) {
+	colour := ant.colours[node]
+	return colour - 1, colour != 0
+}
+
+// Next turns the ant with the step of the current node, updates the node and walks through the heading edge
+func (ant *Ant) Next() {
+	node := &ant.Graph.Nodes[ant.Node]
+	degree := len(node.Edges)
+	index := ant.colours[ant.Node] - 1
+	step := ant.steps[index]
+
+	switch step.Action {
+	case langton.ActionTurnLeft:
+		ant.Heading = (ant.Heading + 1) % degree
+	case langton.ActionTurnRight:
+		ant.Heading = (ant.Heading + degree - 1) % degree
+	case langton.ActionUTurn:
+		ant.Heading = node.Opposite(ant.Heading)
+	}
+
+	ant.colours[ant.Node] = (index+1)%len(ant.steps) + 1
+	ant.totalSteps++
+
+	edge := node.Edges[ant.Heading]
+	if edge.Missing() {
+		ant.Heading = node.Opposite(ant.Heading)
+		return
+	}
+	ant.Node = edge.To
+	ant.Heading = ant.Graph.Nodes[edge.To].Opposite(edge.Back)
+	if ant.colours[ant.Node] == 0 {
+		ant.colours[ant.Node] = 1
+	}
+}
+
+// NextN computes n next steps
+func (ant *Ant) NextN(steps int) {
+	if steps < 0 {
+		panic("steps must be >= 0")
+	}
+	for i := 0; i < steps; i++ {
+		ant.Next()
+	}
+}
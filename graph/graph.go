@@ -0,0 +1,129 @@
+// Package graph runs ants on arbitrary graphs where every node has its edges sorted counter-clockwise.
+// Turning moves to the next or previous edge in that order, so the same rules used by langton.Ant
+// can be followed on aperiodic tilings or on grids with defects.
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Vec is a point of the plane, it is used to draw the nodes
+type Vec struct {
+	X float64
+	Y float64
+}
+
+// Edge links a node with one of its neighbours
+type Edge struct {
+	// To is the neighbour node, -1 if the edge is missing
+	To int
+	// Back is the index of the edge in the neighbour that leads back to this node
+	Back int
+}
+
+// Missing returns true if the edge does not lead anywhere
+func (edge Edge) Missing() bool {
+	return edge.To < 0
+}
+
+// Node is a vertex of the graph
+type Node struct {
+	// Edges are sorted counter-clockwise, the edge i+1 is at the left of the edge i
+	Edges []Edge
+	// Polygon is the shape of the node used by the renderer, vertices are sorted counter-clockwise
+	Polygon []Vec
+}
+
+// Graph is a set of nodes linked by edges
+type Graph struct {
+	Nodes []Node
+}
+
+var ErrInvalidGraph = errors.New("Invalid graph")
+
+// AddNode appends a node with the given number of missing edges and returns its index
+func (graph *Graph) AddNode(edges int, polygon []Vec) int {
+	node := Node{
+		Edges:   make([]Edge, edges),
+		Polygon: polygon,
+	}
+	for i := range node.Edges {
+		node.Edges[i] = Edge{To: -1, Back: -1}
+	}
+	graph.Nodes = append(graph.Nodes, node)
+	return len(graph.Nodes) - 1
+}
+
+// Connect links the edge ea of the node a with the edge eb of the node b in both directions
+func (graph *Graph) Connect(a, ea, b, eb int) {
+	graph.Nodes[a].Edges[ea] = Edge{To: b, Back: eb}
+	graph.Nodes[b].Edges[eb] = Edge{To: a, Back: ea}
+}
+
+// Disconnect removes the edge e of the node n in both directions
+func (graph *Graph) Disconnect(n, e int) {
+	edge := graph.Nodes[n].Edges[e]
+	if edge.Missing() {
+		return
+	}
+	graph.Nodes[edge.To].Edges[edge.Back] = Edge{To: -1, Back: -1}
+	graph.Nodes[n].Edges[e] = Edge{To: -1, Back: -1}
+}
+
+// Validate checks that every edge leads back to its node
+func (graph *Graph) Validate() error {
+	for n, node := range graph.Nodes {
+		if len(node.Edges) == 0 {
+			return fmt.Errorf("%w: node %d has no edges", ErrInvalidGraph, n)
+		}
+		for e, edge := range node.Edges {
+			if edge.Missing() {
+				continue
+			}
+			if edge.To >= len(graph.Nodes) || edge.Back < 0 || edge.Back >= len(graph.Nodes[edge.To].Edges) {
+				return fmt.Errorf("%w: edge %d of node %d is out of range", ErrInvalidGraph, e, n)
+			}
+			back := graph.Nodes[edge.To].Edges[edge.Back]
+			if back.To != n || back.Back != e {
+				return fmt.Errorf("%w: edge %d of node %d does not lead back", ErrInvalidGraph, e, n)
+			}
+		}
+	}
+	return nil
+}
+
+// Nearest returns the node with the polygon center closest to p, -1 if the graph is empty
+func (graph *Graph) Nearest(p Vec) int {
+	nearest := -1
+	best := 0.0
+	for n := range graph.Nodes {
+		c := graph.Nodes[n].Center()
+		d := (c.X-p.X)*(c.X-p.X) + (c.Y-p.Y)*(c.Y-p.Y)
+		if nearest == -1 || d < best {
+			nearest = n
+			best = d
+		}
+	}
+	return nearest
+}
+
+// Center returns the average of the polygon vertices
+func (node *Node) Center() Vec {
+	center := Vec{}
+	for _, v := range node.Polygon {
+		center.X += v.X
+		center.Y += v.Y
+	}
+	if len(node.Polygon) != 0 {
+		center.X /= float64(len(node.Polygon))
+		center.Y /= float64(len(node.Polygon))
+	}
+	return center
+}
+
+// Opposite returns the edge used to walk straight for an ant that entered the node through the edge back.
+// Nodes with an odd number of edges round it down
+func (node *Node) Opposite(back int) int {
+	return (back + len(node.Edges)/2) % len(node.Edges)
+}
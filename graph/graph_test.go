@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"errors"
+	"go-ant/langton"
+	"testing"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+func TestGrid_MatchesLangton(t *testing.T) {
+	const size = 41
+	const awesome = "RLLLLRRRLLL"
+	grid := NewGrid(size, size, 0, nil)
+	if err := grid.Validate(); err != nil {
+		t.Fatalf("Graph.Validate() error = %v", err)
+	}
+	center := size/2*size + size/2
+	ant, err := NewAnt(grid, center, langton.StepsFromString(awesome)...)
+	if err != nil {
+		t.Fatalf("NewAnt() error = %v", err)
+	}
+	ant.Heading = GridNorth
+	want := langton.NewAnt(langton.NewBoard(size/2), langton.StepsFromString(awesome)...)
+
+	for i := 0; i < 3000; i++ {
+		ant.Next()
+		want.Next()
+	}
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			index, ok := ant.Colour(y*size + x)
+			cell, wantOk := want.Board.Cell(langton.Point{X: int64(x - size/2), Y: int64(y - size/2)})
+			if ok != wantOk || (ok && index != cell.Step.Index) {
+				t.Fatalf("Ant.Colour(%d, %d) = %d, %v, want %d, %v", x, y, index, ok, cell.Step.Index, wantOk)
+			}
+		}
+	}
+}
+
+func TestGrid_MissingEdges(t *testing.T) {
+	grid := NewGrid(20, 20, 0.3, langton.NewRandom(1))
+	if err := grid.Validate(); err != nil {
+		t.Fatalf("Graph.Validate() error = %v", err)
+	}
+	missing := 0
+	for _, node := range grid.Nodes {
+		for _, edge := range node.Edges {
+			if edge.Missing() {
+				missing++
+			}
+		}
+	}
+	// the border has 80 missing edges and about 30% of the 760 inner edges are removed from both sides
+	if missing < 80+2*150 || missing > 80+2*310 {
+		t.Errorf("missing edges = %d, want about %d", missing, 80+2*228)
+	}
+
+	ant, err := NewAnt(grid, grid.Nearest(Vec{10, 10}), langton.StepsFromString("LR")...)
+	if err != nil {
+		t.Fatalf("NewAnt() error = %v", err)
+	}
+	ant.NextN(10000)
+	if ant.TotalSteps() != 10000 {
+		t.Errorf("Ant.TotalSteps() = %d, want %d", ant.TotalSteps(), 10000)
+	}
+}
+
+func TestPenrose(t *testing.T) {
+	penrose := NewPenrose(5)
+	if err := penrose.Validate(); err != nil {
+		t.Fatalf("Graph.Validate() error = %v", err)
+	}
+	inner := 0
+	for n, node := range penrose.Nodes {
+		for e := 0; e < 4; e++ {
+			a := node.Polygon[e]
+			b := node.Polygon[(e+1)%4]
+			side := (a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y)
+			if side < 0.99 || side > 1.01 {
+				t.Fatalf("node %d side %d has length %f, want 1", n, e, side)
+			}
+		}
+		linked := 0
+		for _, edge := range node.Edges {
+			if !edge.Missing() {
+				linked++
+			}
+		}
+		if linked == 4 {
+			inner++
+		}
+	}
+	if inner < len(penrose.Nodes)/2 {
+		t.Errorf("%d of %d rhombi have 4 neighbours, want most of them", inner, len(penrose.Nodes))
+	}
+
+	ant, err := NewAnt(penrose, penrose.Nearest(Vec{}), langton.StepsFromString("LR")...)
+	if err != nil {
+		t.Fatalf("NewAnt() error = %v", err)
+	}
+	ant.NextN(2000)
+	palette, err := colorful.SoftPalette(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := ToImage(ant, langton.ToPalette(palette), 10)
+	if img.Bounds().Dx() < 100 {
+		t.Errorf("ToImage() width = %d, want at least 100", img.Bounds().Dx())
+	}
+}
+
+func TestNewAnt_Stochastic(t *testing.T) {
+	_, err := NewAnt(NewGrid(3, 3, 0, nil), 4, langton.StepsFromString("[L0.5R]")...)
+	if err == nil {
+		t.Errorf("NewAnt() error = nil, want error")
+	}
+}
+
+func TestNewAnt_NoEdges(t *testing.T) {
+	_, err := NewAnt(&Graph{Nodes: []Node{{}}}, 0, langton.StepsFromString("LR")...)
+	if !errors.Is(err, ErrInvalidGraph) {
+		t.Errorf("NewAnt() error = %v, want %v", err, ErrInvalidGraph)
+	}
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/colornames"
+)
+
+// ToImage draws every node polygon filled with the colour of its step, scale is the number of pixels per unit.
+// The palette is used as in langton.ToImage, the first colour is skipped and step i takes the colour i+1.
+// Nodes never visited are left transparent, the borders of the tiles are drawn in grey and the ant as a black dot.
+// Polygons must be convex
+func ToImage(ant *Ant, palette color.Palette, scale float64) *image.RGBA {
+	graph := ant.Graph
+	min, max := graph.bounds()
+	img := image.NewRGBA(image.Rect(
+		0,
+		0,
+		int(math.Ceil((max.X-min.X)*scale))+1,
+		int(math.Ceil((max.Y-min.Y)*scale))+1,
+	))
+	toPixels := func(v Vec) Vec {
+		return Vec{
+			X: (v.X - min.X) * scale,
+			Y: (v.Y - min.Y) * scale,
+		}
+	}
+
+	polygon := []Vec{}
+	for n := range graph.Nodes {
+		polygon = polygon[:0]
+		for _, v := range graph.Nodes[n].Polygon {
+			polygon = append(polygon, toPixels(v))
+		}
+		var fill color.Color
+		if index, ok := ant.Colour(n); ok {
+			fill = palette[index+1]
+		}
+		drawPolygon(img, polygon, fill, colornames.Grey)
+	}
+
+	center := toPixels(graph.Nodes[ant.Node].Center())
+	radius := scale / 6
+	for x := int(center.X - radius); x <= int(center.X+radius); x++ {
+		for y := int(center.Y - radius); y <= int(center.Y+radius); y++ {
+			dx := float64(x) + 0.5 - center.X
+			dy := float64(y) + 0.5 - center.Y
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(x, y, colornames.Black)
+			}
+		}
+	}
+	return img
+}
+
+// bounds returns the corners of the box that contains every polygon
+func (graph *Graph) bounds() (min, max Vec) {
+	min = Vec{math.Inf(1), math.Inf(1)}
+	max = Vec{math.Inf(-1), math.Inf(-1)}
+	for _, node := range graph.Nodes {
+		for _, v := range node.Polygon {
+			min.X = math.Min(min.X, v.X)
+			min.Y = math.Min(min.Y, v.Y)
+			max.X = math.Max(max.X, v.X)
+			max.Y = math.Max(max.Y, v.Y)
+		}
+	}
+	if min.X > max.X {
+		return Vec{}, Vec{}
+	}
+	return min, max
+}
+
+// drawPolygon fills the pixels with the center inside the convex polygon, the pixels close to its sides take the border colour.
+// A nil fill leaves the inside untouched
+func drawPolygon(img *image.RGBA, polygon []Vec, fill, border color.Color) {
+	if len(polygon) < 3 {
+		return
+	}
+	minX, minY := polygon[0].X, polygon[0].Y
+	maxX, maxY := minX, minY
+	for _, v := range polygon {
+		minX = math.Min(minX, v.X)
+		minY = math.Min(minY, v.Y)
+		maxX = math.Max(maxX, v.X)
+		maxY = math.Max(maxY, v.Y)
+	}
+	for y := int(minY); y <= int(maxY); y++ {
+		for x := int(minX); x <= int(maxX); x++ {
+			p := Vec{float64(x) + 0.5, float64(y) + 0.5}
+			inside, distance := insideConvex(polygon, p)
+			switch {
+			case !inside:
+			case distance < 0.5:
+				img.Set(x, y, border)
+			case fill != nil:
+				img.Set(x, y, fill)
+			}
+		}
+	}
+}
+
+// insideConvex returns true if p is inside the counter-clockwise convex polygon and the distance to its closest side
+func insideConvex(polygon []Vec, p Vec) (bool, float64) {
+	closest := math.Inf(1)
+	for i := range polygon {
+		a := polygon[i]
+		b := polygon[(i+1)%len(polygon)]
+		dx, dy := b.X-a.X, b.Y-a.Y
+		length := math.Hypot(dx, dy)
+		if length == 0 {
+			continue
+		}
+		// signed distance to the line, positive at the left of a->b
+		distance := (dx*(p.Y-a.Y) - dy*(p.X-a.X)) / length
+		if distance < 0 {
+			return false, 0
+		}
+		closest = math.Min(closest, distance)
+	}
+	return true, closest
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"go-ant/langton"
+	"math"
+	"math/cmplx"
+)
+
+// Grid edges, sorted counter-clockwise
+const (
+	GridEast = iota
+	GridNorth
+	GridWest
+	GridSouth
+)
+
+// NewGrid creates a square grid where the node of the cell x, y has the index y*width+x.
+// Every edge is removed with the probability given by missing, drawn from the random source.
+// An ant that starts facing GridNorth on a grid without missing edges walks like a langton.Ant
+func NewGrid(width, height int, missing float64, random *langton.Random) *Graph {
+	graph := &Graph{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			fx, fy := float64(x), float64(y)
+			graph.AddNode(4, []Vec{
+				{fx, fy},
+				{fx + 1, fy},
+				{fx + 1, fy + 1},
+				{fx, fy + 1},
+			})
+		}
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			n := y*width + x
+			if x+1 < width && (missing <= 0 || random.Float64() >= missing) {
+				graph.Connect(n, GridEast, n+1, GridWest)
+			}
+			if y+1 < height && (missing <= 0 || random.Float64() >= missing) {
+				graph.Connect(n, GridNorth, n+width, GridSouth)
+			}
+		}
+	}
+	return graph
+}
+
+// goldenRatio is the ratio between the sides of the Robinson triangles
+var goldenRatio = (1 + math.Sqrt(5)) / 2
+
+// robinson is half of a Penrose rhombus, thin is false for the halves of the fat rhombus.
+// Two triangles that share the side bc form a rhombus
+type robinson struct {
+	thin    bool
+	a, b, c complex128
+}
+
+// NewPenrose creates a Penrose rhombus tiling, every node is a rhombus linked to the rhombi that share a side with it.
+// The tiling starts as a wheel of 10 Robinson triangles around the origin that is subdivided the given number of generations,
+// tiles have sides of length 1. Rhombi at the border have missing edges
+func NewPenrose(generations int) *Graph {
+	radius := math.Pow(goldenRatio, float64(generations))
+	triangles := make([]robinson, 0, 10)
+	for i := 0; i < 10; i++ {
+		b := cmplx.Rect(radius, float64(2*i-1)*math.Pi/10)
+		c := cmplx.Rect(radius, float64(2*i+1)*math.Pi/10)
+		if i%2 == 0 {
+			b, c = c, b
+		}
+		triangles = append(triangles, robinson{a: 0, b: b, c: c})
+	}
+	for i := 0; i < generations; i++ {
+		triangles = subdivide(triangles)
+	}
+	return rhombi(triangles)
+}
+
+// subdivide splits every triangle in smaller Robinson triangles
+func subdivide(triangles []robinson) []robinson {
+	out := make([]robinson, 0, len(triangles)*3)
+	for _, t := range triangles {
+		if !t.thin {
+			p := t.a + (t.b-t.a)/complex(goldenRatio, 0)
+			out = append(out,
+				robinson{thin: false, a: t.c, b: p, c: t.b},
+				robinson{thin: true, a: p, b: t.c, c: t.a},
+			)
+			continue
+		}
+		q := t.b + (t.a-t.b)/complex(goldenRatio, 0)
+		r := t.b + (t.c-t.b)/complex(goldenRatio, 0)
+		out = append(out,
+			robinson{thin: true, a: r, b: t.c, c: t.a},
+			robinson{thin: true, a: q, b: r, c: t.b},
+			robinson{thin: false, a: r, b: q, c: t.a},
+		)
+	}
+	return out
+}
+
+// vertexKey identifies a vertex ignoring floating point errors
+type vertexKey struct {
+	x, y int64
+}
+
+func keyOf(v complex128) vertexKey {
+	return vertexKey{
+		x: int64(math.Round(real(v) * 1e6)),
+		y: int64(math.Round(imag(v) * 1e6)),
+	}
+}
+
+// sideKey identifies a side shared by two rhombi
+type sideKey struct {
+	from, to vertexKey
+}
+
+func sideOf(a, b complex128) sideKey {
+	ka, kb := keyOf(a), keyOf(b)
+	if ka.x > kb.x || (ka.x == kb.x && ka.y > kb.y) {
+		ka, kb = kb, ka
+	}
+	return sideKey{ka, kb}
+}
+
+// sideRef is a side of a node
+type sideRef struct {
+	node, edge int
+}
+
+// rhombi joins the triangles that share the side bc and links the rhombi that share a side.
+// Triangles without their other half are dropped
+func rhombi(triangles []robinson) *Graph {
+	halves := map[sideKey]int{}
+	graph := &Graph{}
+	sides := map[sideKey]sideRef{}
+	for i, t := range triangles {
+		key := sideOf(t.b, t.c)
+		j, found := halves[key]
+		if !found {
+			halves[key] = i
+			continue
+		}
+		delete(halves, key)
+
+		other := triangles[j]
+		vertices := []complex128{t.a, t.b, other.a, t.c}
+		if area(vertices) < 0 {
+			vertices = []complex128{t.a, t.c, other.a, t.b}
+		}
+		polygon := make([]Vec, len(vertices))
+		for v := range vertices {
+			polygon[v] = Vec{X: real(vertices[v]), Y: imag(vertices[v])}
+		}
+		node := graph.AddNode(4, polygon)
+		for e := range vertices {
+			side := sideOf(vertices[e], vertices[(e+1)%len(vertices)])
+			if neighbour, ok := sides[side]; ok {
+				graph.Connect(node, e, neighbour.node, neighbour.edge)
+				delete(sides, side)
+				continue
+			}
+			sides[side] = sideRef{node: node, edge: e}
+		}
+	}
+	return graph
+}
+
+// area returns the signed area of the polygon, positive if it is sorted counter-clockwise
+func area(vertices []complex128) float64 {
+	sum := 0.0
+	for i := range vertices {
+		a := vertices[i]
+		b := vertices[(i+1)%len(vertices)]
+		sum += real(a)*imag(b) - real(b)*imag(a)
+	}
+	return sum / 2
+}
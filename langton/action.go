@@ -12,6 +12,8 @@ func (action Action) String() string {
 		return "Right"
 	case ActionStraight:
 		return "Straight"
+	case ActionUTurn:
+		return "U-turn"
 	default:
 		return "Unknown"
 	}
@@ -26,4 +28,6 @@ const (
 	ActionTurnRight = 'R'
 	// ActionStraight does not change direction
 	ActionStraight = 'S'
+	// ActionUTurn turns back
+	ActionUTurn = 'U'
 )
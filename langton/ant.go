@@ -6,15 +6,28 @@ import (
 )
 
 type Ant struct {
-	Cells     []Cell
-	Position  *Cell
+	Board     Board
+	Position  Cell
 	Direction Direction
 
-	Dimensions Dimensions
-
 	steps      []Step
 	totalSteps int64
 	stuck      bool
+	visited    Dimensions
+	histogram  []int64
+	boardHash  uint64
+	// untracked disables the histogram and hash updates while the board is rebuilt
+	untracked bool
+
+	random     *Random
+	noise      Noise
+	lastAction Action
+	schedule   *scheduleState
+	sensing    *SensingRule
+	turmite    *turmiteState
+
+	// changed is notified of every cell stored in the board
+	changed func(p Point)
 }
 
 // NewAntFromString creates a new ant in a board with the given Dimensions for a sequence defined by a string of LR characters
@@ -22,22 +35,93 @@ func NewAntFromString(dimensions Dimensions, steps string) *Ant {
 	return NewAnt(dimensions, StepsFromString(steps)...)
 }
 
-// NewAnt creates a new ant in a board with the given Dimensions following the steps
+// NewAnt creates a new ant in a DenseBoard with the given Dimensions following the steps
 func NewAnt(dimensions Dimensions, steps ...Step) *Ant {
+	return NewAntOnBoard(NewDenseBoard(dimensions), steps...)
+}
+
+// NewAntOnBoard creates a new ant in the center of the given board following the steps.
+// Cells already stored in the board are kept
+func NewAntOnBoard(board Board, steps ...Step) *Ant {
+	dimensions := board.Dimensions()
+	ant, err := newAntAt(board, dimensions.Center(), steps...)
+	if err != nil {
+		panic(err)
+	}
+	return ant
+}
+
+// newAntAt creates a new ant at the given position of the board, the cell is visited if it was not
+func newAntAt(board Board, position Point, steps ...Step) (*Ant, error) {
 
 	Steps(steps).Numerate()
 
-	cells := make([]Cell, dimensions.Size, dimensions.Size)
-	cell := &cells[dimensions.indexOf(dimensions.Center())]
-	cell.Point = dimensions.Center()
-	cell.Step = steps[0]
+	ant := &Ant{
+		Board:  board,
+		steps:  steps,
+		random: NewRandom(0),
+	}
+	cell, _, err := ant.ensureCellAt(position)
+	if err != nil {
+		return nil, err
+	}
+	ant.Position = cell
+	ant.visited = NewDimensions(cell.X, cell.Y, cell.X, cell.Y)
+	board.Each(func(cell Cell) bool {
+		ant.visited = ant.visited.extend(cell.Point)
+		return true
+	})
+	ant.retrack()
+	ant.setCell(cell)
+	return ant, nil
+}
+
+// Clone returns an independent copy of the ant and its board that continues exactly as the original would.
+// Views of a SyncAnt are not notified of the changes made by the copy
+func (ant *Ant) Clone() *Ant {
+	clone := *ant
+	clone.Board = ant.Board.Empty(ant.Dimensions())
+	copyBoard(clone.Board, ant.Board)
+	clone.histogram = append([]int64{}, ant.histogram...)
+	random := *ant.random
+	clone.random = &random
+	if ant.turmite != nil {
+		turmite := *ant.turmite
+		clone.turmite = &turmite
+	}
+	if ant.schedule != nil {
+		schedule := *ant.schedule
+		clone.schedule = &schedule
+	}
+	if ant.sensing != nil {
+		sensing := *ant.sensing
+		sensing.cache = nil
+		clone.sensing = &sensing
+	}
+	clone.changed = nil
+	return &clone
+}
+
+// Seed sets the seed of the random source used by stochastic steps and noise
+func (ant *Ant) Seed(seed int64) {
+	ant.random.Seed(seed)
+}
 
-	return &Ant{
-		Cells:      cells,
-		Position:   cell,
-		steps:      steps,
-		Dimensions: dimensions,
+// SetNoise sets the noise applied to the board after every step, nil disables it
+func (ant *Ant) SetNoise(noise Noise) error {
+	if noise != nil {
+		err := noise.Validate()
+		if err != nil {
+			return err
+		}
 	}
+	ant.noise = noise
+	return nil
+}
+
+// LastAction returns the action taken in the last step
+func (ant *Ant) LastAction() Action {
+	return ant.lastAction
 }
 
 // TotalSteps returns the total steps performed by the ant
@@ -45,6 +129,21 @@ func (ant *Ant) TotalSteps() int64 {
 	return ant.totalSteps
 }
 
+// Steps returns the sequence of steps followed by the ant
+func (ant *Ant) Steps() Steps {
+	return ant.steps
+}
+
+// Dimensions returns the dimensions of the board
+func (ant *Ant) Dimensions() Dimensions {
+	return ant.Board.Dimensions()
+}
+
+// Visited returns the bounding box of the cells visited by the ant
+func (ant *Ant) Visited() Dimensions {
+	return ant.visited
+}
+
 // Stuck returns true if the ant can not move because it will fall out the board
 func (ant *Ant) Stuck() bool {
 	return ant.stuck
@@ -56,25 +155,65 @@ func (ant *Ant) Next() (*Cell, error) {
 		return nil, errors.New("Ant is stuck, grow the grid before calling Next")
 	}
 
-	ant.Direction = ant.Direction.Turn(ant.Position.Step.Action)
+	cell, err := ant.move(ant.action())
+	if err != nil {
+		return cell, err
+	}
+	ant.updateSchedule()
+	if ant.noise != nil {
+		ant.noise.Apply(ant, ant.random)
+	}
+	return cell, nil
+}
 
-	ant.Position.UpdateNextStep(ant.steps)
+// action returns the action for the current cell.
+// A turmite has priority, then the sensing rule, stochastic steps draw it from the random source
+func (ant *Ant) action() Action {
+	if ant.turmite != nil {
+		return ant.turmite.rule(ant.Position.Step.Index).Turn
+	}
+	if ant.sensing != nil {
+		action, ok := ant.sensing.Action(ant)
+		if ok {
+			return action
+		}
+	}
+	step := ant.Position.Step
+	if !step.Stochastic() || ant.random.Float64() < step.Probability {
+		return step.Action
+	}
+	return step.Alternative
+}
 
-	nextPoint := ant.Position.Point.Walk(ant.Direction)
+// move turns the ant with the given action, updates the current cell and walks forward
+func (ant *Ant) move(action Action) (*Cell, error) {
+	direction := ant.Direction.Turn(action)
 
-	nextPosition, err := ant.ensureCellAt(nextPoint)
+	nextPosition, visited, err := ant.ensureCellAt(ant.Position.Point.Walk(direction))
 	if err != nil {
 		ant.stuck = true
+		return &ant.Position, err
+	}
 
-		ant.Position.UpdatePreviousStep(ant.steps)
-		ant.Direction = ant.Direction.Unturn(ant.Position.Step.Action)
+	ant.lastAction = action
+	ant.Direction = direction
 
-		return ant.Position, err
+	current := ant.Position
+	if ant.turmite != nil {
+		rule := ant.turmite.rule(current.Step.Index)
+		current.Step = ant.steps[rule.Colour]
+		ant.turmite.state = rule.State
+	} else {
+		current.UpdateNextStep(ant.steps)
+	}
+	ant.setCell(current)
+	if !visited {
+		ant.setCell(nextPosition)
 	}
 	ant.Position = nextPosition
 
 	ant.totalSteps++
-	return ant.Position, nil
+	return &ant.Position, nil
 }
 
 // NextN computes n next steps and returns the cell position, Fails if it moves out the board
@@ -83,7 +222,7 @@ func (ant *Ant) NextN(steps int) (cell *Cell, err error) {
 		panic("steps must be >= 0")
 	}
 	if steps == 0 {
-		return ant.Position, nil
+		return &ant.Position, nil
 	}
 	for i := 0; i < steps; i++ {
 		cell, err = ant.Next()
@@ -101,62 +240,137 @@ var (
 
 // CellAt returns the cell at the given coordinates. It fails if the ant has never visited that cell
 func (ant *Ant) CellAt(position Point) (*Cell, error) {
-	if !ant.Dimensions.isPointInside(position) {
+	dimensions := ant.Board.Dimensions()
+	if !dimensions.Contains(position) {
 		return nil, ErrOutOfBounds
 	}
-	posIndex := ant.Dimensions.indexOf(position)
-	cell := &ant.Cells[posIndex]
-	if cell.Step.Action == ActionNone {
+	cell, ok := ant.Board.Cell(position)
+	if !ok {
 		return nil, ErrNotInitialized
 	}
-	return cell, nil
+	return &cell, nil
 }
 
-// ensureCellAt creates or returns the cell at the given position
-func (ant *Ant) ensureCellAt(position Point) (*Cell, error) {
-	if !ant.Dimensions.isPointInside(position) {
-		return nil, errors.New("Next step is out of bounds")
+// ensureCellAt returns the cell at the given position, or a new cell that is not stored yet if it has never been visited
+func (ant *Ant) ensureCellAt(position Point) (cell Cell, visited bool, err error) {
+	cell, ok := ant.Board.Cell(position)
+	if ok {
+		return cell, true, nil
 	}
-	posIndex := ant.Dimensions.indexOf(position)
-	cell := &ant.Cells[posIndex]
-	if cell.Step.Action == ActionNone {
-		ant.Cells[posIndex] = Cell{
-			Point: position,
-			Step:  ant.steps[0],
-		}
-		cell = &ant.Cells[posIndex]
+	dimensions := ant.Board.Dimensions()
+	if !dimensions.Contains(position) {
+		return Cell{}, false, ErrOutOfBounds
 	}
-	return cell, nil
+	return Cell{
+		Point: position,
+		Step:  ant.steps[0],
+	}, false, nil
+}
+
+// setCell stores the cell in the board, keeping the ant position in sync.
+// Every modification of the board must go through here
+func (ant *Ant) setCell(cell Cell) error {
+	var (
+		old     Cell
+		visited bool
+	)
+	if !ant.untracked {
+		old, visited = ant.Board.Cell(cell.Point)
+	}
+	err := ant.Board.SetCell(cell)
+	if err != nil {
+		return err
+	}
+	if !ant.untracked {
+		ant.track(old, visited, cell)
+	}
+	if cell.Point == ant.Position.Point {
+		ant.Position = cell
+	}
+	if cell.Step.Action != ActionNone {
+		ant.visited = ant.visited.extend(cell.Point)
+	}
+	if ant.changed != nil {
+		ant.changed(cell.Point)
+	}
+	return nil
+}
+
+// track updates the histogram and the hash when old is replaced by cell
+func (ant *Ant) track(old Cell, visited bool, cell Cell) {
+	if visited {
+		ant.histogram[old.Step.Index]--
+		ant.boardHash ^= hashCell(old)
+	}
+	if cell.Step.Action != ActionNone {
+		ant.histogram[cell.Step.Index]++
+		ant.boardHash ^= hashCell(cell)
+	}
+}
+
+// retrack rebuilds the histogram and the hash scanning the whole board
+func (ant *Ant) retrack() {
+	ant.histogram = make([]int64, len(ant.steps))
+	ant.boardHash = 0
+	ant.Board.Each(func(cell Cell) bool {
+		ant.histogram[cell.Step.Index]++
+		ant.boardHash ^= hashCell(cell)
+		return true
+	})
+}
+
+// setStep changes the step of the cell at the given position
+func (ant *Ant) setStep(position Point, step Step) error {
+	cell, _, err := ant.ensureCellAt(position)
+	if err != nil {
+		return err
+	}
+	cell.Step = step
+	return ant.setCell(cell)
 }
 
 // Grow increases the grid dimensions, fails if the dimensions provided are smaller than or equal to the current dimension
+// or if they do not contain the visited cells
 func (ant *Ant) Grow(dimensions Dimensions) error {
-	if ant.Dimensions.height >= dimensions.height || ant.Dimensions.width >= dimensions.width {
+	current := ant.Board.Dimensions()
+	if current.height >= dimensions.height || current.width >= dimensions.width {
 		return errors.New("New dimensions are equal or smaller than the current dimensions")
 	}
-
-	newCells := make([]Cell, dimensions.Size, dimensions.Size)
-	for i := range ant.Cells {
-		old := ant.Cells[i]
-		if old.Step.Action == ActionNone {
-			continue
-		}
-		newCells[dimensions.indexOf(old.Point)] = old
+	if !dimensions.ContainsDimensions(ant.visited) {
+		return errors.New("New dimensions do not contain the visited cells")
 	}
-	ant.Cells = newCells
-	ant.Dimensions = dimensions
-	ant.Position = &newCells[dimensions.indexOf(ant.Position.Point)]
+
+	ant.resize(dimensions)
 	ant.stuck = false
 	return nil
 }
 
+// Crop shrinks the grid dimensions to the visited cells plus a margin, the opposite of Grow.
+// The margin never takes the grid beyond its current dimensions
+func (ant *Ant) Crop(margin int64) error {
+	if margin < 0 {
+		return errors.New("margin must be >= 0")
+	}
+	dimensions, _ := ant.visited.Expand(margin).Intersect(ant.Board.Dimensions())
+	ant.resize(dimensions)
+	return nil
+}
+
+// resize moves the visited cells to a new board with the given dimensions
+func (ant *Ant) resize(dimensions Dimensions) {
+	board := ant.Board.Empty(dimensions)
+	copyBoard(board, ant.Board)
+	ant.Board = board
+}
+
 // StringMargin returns a string representation of the board with a given margin.
 // It is useful for testing purposes
 func (ant *Ant) StringMargin(margin int64) string {
-	minX := ant.Dimensions.BottomLeft.X - margin
-	minY := ant.Dimensions.BottomLeft.Y - margin
-	maxX := ant.Dimensions.TopRight.X + margin
-	maxY := ant.Dimensions.TopRight.Y + margin
+	dimensions := ant.Board.Dimensions()
+	minX := dimensions.BottomLeft.X - margin
+	minY := dimensions.BottomLeft.Y - margin
+	maxX := dimensions.TopRight.X + margin
+	maxY := dimensions.TopRight.Y + margin
 
 	builder := strings.Builder{}
 	builder.Grow(int((maxX - minX) * (maxY - minY)))
@@ -166,12 +380,10 @@ func (ant *Ant) StringMargin(margin int64) string {
 				X: x,
 				Y: y,
 			}
-			if ant.Dimensions.isPointInside(p) {
-				cell := ant.Cells[ant.Dimensions.indexOf(p)]
-				if cell.Step.Action != ActionNone {
-					builder.WriteRune(rune(cell.Step.Action))
-					continue
-				}
+			cell, ok := ant.Board.Cell(p)
+			if ok {
+				builder.WriteRune(rune(cell.Step.Action))
+				continue
 			}
 
 			switch {
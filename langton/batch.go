@@ -0,0 +1,237 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+)
+
+// batchChunk is the number of steps performed by each ant before moving to the next one
+const batchChunk = 4096
+
+// Batch simulates many ants with different rules in lockstep, each one in its own board.
+// The state is kept in a struct of arrays layout and boards use a single byte per cell,
+// so it is much cheaper than running an Ant per rule when sweeping rule space.
+// Stochastic steps, noise, schedules and sensing rules are not supported
+type Batch struct {
+	dimensions Dimensions
+	rules      []Steps
+
+	// turns holds, for every ant and step index, the change of direction, 0 straight, 1 right, 2 back, 3 left
+	turns      []uint8
+	ruleOffset []int
+
+	indexes    []uint8
+	x          []int64
+	y          []int64
+	direction  []Direction
+	totalSteps []int64
+	stuck      []bool
+	visited    []Dimensions
+}
+
+// BatchResult is the state of an ant of the Batch
+type BatchResult struct {
+	Steps      Steps
+	TotalSteps int64
+	Stuck      bool
+	Position   Point
+	Direction  Direction
+	// Visited is the bounding box of the visited cells
+	Visited Dimensions
+}
+
+var ErrUnsupportedRule = errors.New("Rule not supported")
+
+// NewBatch creates a batch with an ant per rule, all of them in boards with the given dimensions
+func NewBatch(dimensions Dimensions, rules ...Steps) (*Batch, error) {
+	batch := &Batch{
+		dimensions: dimensions,
+		rules:      make([]Steps, len(rules)),
+		ruleOffset: make([]int, len(rules)),
+		indexes:    make([]uint8, dimensions.Size*int64(len(rules))),
+		x:          make([]int64, len(rules)),
+		y:          make([]int64, len(rules)),
+		direction:  make([]Direction, len(rules)),
+		totalSteps: make([]int64, len(rules)),
+		stuck:      make([]bool, len(rules)),
+		visited:    make([]Dimensions, len(rules)),
+	}
+	center := dimensions.Center()
+	for i, rule := range rules {
+		if len(rule) == 0 || len(rule) > MaxCompactSteps {
+			return nil, fmt.Errorf("%w: rule %d must have between 1 and %d steps", ErrUnsupportedRule, i, MaxCompactSteps)
+		}
+		batch.rules[i] = numerated(rule)
+		batch.ruleOffset[i] = len(batch.turns)
+		for _, step := range rule {
+			if step.Stochastic() {
+				return nil, fmt.Errorf("%w: stochastic step in rule %s", ErrUnsupportedRule, rule)
+			}
+			switch step.Action {
+			case ActionStraight:
+				batch.turns = append(batch.turns, 0)
+			case ActionTurnRight:
+				batch.turns = append(batch.turns, 1)
+			case ActionTurnLeft:
+				batch.turns = append(batch.turns, 3)
+			case ActionUTurn:
+				batch.turns = append(batch.turns, 2)
+			default:
+				return nil, fmt.Errorf("%w: unknown action in rule %s", ErrUnsupportedRule, rule)
+			}
+		}
+
+		batch.x[i] = center.X - dimensions.BottomLeft.X
+		batch.y[i] = center.Y - dimensions.BottomLeft.Y
+		batch.board(i)[dimensions.indexOf(center)] = 1
+		batch.visited[i] = NewDimensions(center.X, center.Y, center.X, center.Y)
+	}
+	return batch, nil
+}
+
+// Len returns the number of ants in the batch
+func (batch *Batch) Len() int {
+	return len(batch.rules)
+}
+
+// Run advances every ant that is not stuck the given number of steps
+func (batch *Batch) Run(steps int64) {
+	for done := int64(0); done < steps; done += batchChunk {
+		chunk := steps - done
+		if chunk > batchChunk {
+			chunk = batchChunk
+		}
+		for i := range batch.rules {
+			batch.advance(i, chunk)
+		}
+	}
+}
+
+// Done returns true if every ant is stuck
+func (batch *Batch) Done() bool {
+	for _, stuck := range batch.stuck {
+		if !stuck {
+			return false
+		}
+	}
+	return true
+}
+
+// advance moves the ant i the given number of steps, stops if it gets stuck
+func (batch *Batch) advance(i int, steps int64) {
+	if batch.stuck[i] {
+		return
+	}
+	var (
+		board     = batch.board(i)
+		turns     = batch.turns[batch.ruleOffset[i] : batch.ruleOffset[i]+len(batch.rules[i])]
+		colors    = uint8(len(turns))
+		width     = batch.dimensions.width
+		height    = batch.dimensions.height
+		x         = batch.x[i]
+		y         = batch.y[i]
+		direction = batch.direction[i]
+		minX      = batch.visited[i].BottomLeft.X - batch.dimensions.BottomLeft.X
+		minY      = batch.visited[i].BottomLeft.Y - batch.dimensions.BottomLeft.Y
+		maxX      = batch.visited[i].TopRight.X - batch.dimensions.BottomLeft.X
+		maxY      = batch.visited[i].TopRight.Y - batch.dimensions.BottomLeft.Y
+		done      int64
+	)
+	for ; done < steps; done++ {
+		index := y*width + x
+		color := board[index] - 1
+
+		nextDirection := (direction + Direction(turns[color])) % DirectionInvalid
+		nx, ny := x, y
+		switch nextDirection {
+		case DirectionTop:
+			ny++
+		case DirectionRight:
+			nx++
+		case DirectionDown:
+			ny--
+		case DirectionLeft:
+			nx--
+		}
+		if nx < 0 || ny < 0 || nx >= width || ny >= height {
+			batch.stuck[i] = true
+			break
+		}
+
+		color++
+		if color == colors {
+			color = 0
+		}
+		board[index] = color + 1
+
+		x, y, direction = nx, ny, nextDirection
+		next := y*width + x
+		if board[next] == 0 {
+			board[next] = 1
+		}
+
+		if x < minX {
+			minX = x
+		}
+		if x > maxX {
+			maxX = x
+		}
+		if y < minY {
+			minY = y
+		}
+		if y > maxY {
+			maxY = y
+		}
+	}
+
+	origin := batch.dimensions.BottomLeft
+	batch.x[i] = x
+	batch.y[i] = y
+	batch.direction[i] = direction
+	batch.totalSteps[i] += done
+	batch.visited[i] = NewDimensions(origin.X+minX, origin.Y+minY, origin.X+maxX, origin.Y+maxY)
+}
+
+// board returns the cells of the ant i
+func (batch *Batch) board(i int) []uint8 {
+	size := batch.dimensions.Size
+	return batch.indexes[int64(i)*size : int64(i+1)*size]
+}
+
+// Result returns the state of the ant i
+func (batch *Batch) Result(i int) BatchResult {
+	return BatchResult{
+		Steps:      batch.rules[i],
+		TotalSteps: batch.totalSteps[i],
+		Stuck:      batch.stuck[i],
+		Position: Point{
+			X: batch.dimensions.BottomLeft.X + batch.x[i],
+			Y: batch.dimensions.BottomLeft.Y + batch.y[i],
+		},
+		Direction: batch.direction[i],
+		Visited:   batch.visited[i],
+	}
+}
+
+// Board returns a CompactBoard that shares the cells of the ant i, it must not be modified
+func (batch *Batch) Board(i int) *CompactBoard {
+	return &CompactBoard{
+		dimensions: batch.dimensions,
+		steps:      batch.rules[i],
+		indexes:    batch.board(i),
+	}
+}
+
+// Ant returns a copy of the ant i that can be rendered or keep walking on its own
+func (batch *Batch) Ant(i int) *Ant {
+	result := batch.Result(i)
+	board := NewCompactBoard(batch.dimensions, result.Steps)
+	copy(board.indexes, batch.board(i))
+
+	ant := NewAntOnBoard(board, numerated(result.Steps)...)
+	ant.Position, _ = board.Cell(result.Position)
+	ant.Direction = result.Direction
+	ant.totalSteps = result.TotalSteps
+	ant.stuck = result.Stuck
+	return ant
+}
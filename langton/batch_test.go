@@ -0,0 +1,81 @@
+package langton
+
+import "testing"
+
+func TestBatch_MatchesAnt(t *testing.T) {
+	rules := []string{"LR", "RLLLLRRRLLL", "LLRR", "LRS", "LLRRRLRRRRR"}
+	steps := make([]Steps, len(rules))
+	for i := range rules {
+		steps[i] = StepsFromString(rules[i])
+	}
+	// the wide board checks that rows are as long as the width of the dimensions
+	for _, board := range []Dimensions{NewBoard(20), NewDimensions(-30, -12, 30, 12)} {
+		batch, err := NewBatch(board, steps...)
+		if err != nil {
+			t.Fatalf("NewBatch() error = %v", err)
+		}
+		batch.Run(10000)
+		testBatchMatchesAnt(t, batch, board, rules)
+	}
+}
+
+func testBatchMatchesAnt(t *testing.T, batch *Batch, board Dimensions, rules []string) {
+	for i, rule := range rules {
+		t.Run(board.String()+"/"+rule, func(t *testing.T) {
+			ant := NewAntFromString(board, rule)
+			ant.NextN(10000)
+
+			result := batch.Result(i)
+			if result.TotalSteps != ant.TotalSteps() || result.Stuck != ant.Stuck() {
+				t.Errorf("Batch.Result() steps = %d stuck = %v, want %d %v", result.TotalSteps, result.Stuck, ant.TotalSteps(), ant.Stuck())
+			}
+			if result.Position != ant.Position.Point || result.Direction != ant.Direction {
+				t.Errorf("Batch.Result() position = %s %d, want %s %d", result.Position, result.Direction, ant.Position.Point, ant.Direction)
+			}
+			if got := batch.Ant(i).String(); got != ant.String() {
+				t.Errorf("Batch.Ant() = \n%s, want \n%s", got, ant)
+			}
+
+			visited := NewDimensions(0, 0, 0, 0)
+			ant.Board.Each(func(cell Cell) bool {
+				if cell.X < visited.BottomLeft.X {
+					visited.BottomLeft.X = cell.X
+				}
+				if cell.Y < visited.BottomLeft.Y {
+					visited.BottomLeft.Y = cell.Y
+				}
+				if cell.X > visited.TopRight.X {
+					visited.TopRight.X = cell.X
+				}
+				if cell.Y > visited.TopRight.Y {
+					visited.TopRight.Y = cell.Y
+				}
+				return true
+			})
+			visited.Init()
+			if result.Visited != visited {
+				t.Errorf("Batch.Result() visited = %v, want %v", result.Visited, visited)
+			}
+		})
+	}
+}
+
+func TestNewBatch_Unsupported(t *testing.T) {
+	_, err := NewBatch(NewBoard(5), StepsFromString("[L0.5R]R"))
+	if err == nil {
+		t.Errorf("NewBatch() expected error for stochastic rules")
+	}
+}
+
+func BenchmarkBatch(b *testing.B) {
+	rules := make([]Steps, 16)
+	for i := range rules {
+		rules[i] = StepsFromString("RLLLLRRRLLL")
+	}
+	batch, err := NewBatch(NewBoard(500), rules...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	batch.Run(int64(b.N))
+}
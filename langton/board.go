@@ -0,0 +1,244 @@
+package langton
+
+import "fmt"
+
+// Board stores the cells visited by the ant.
+// A cell is visited if its Step.Action is not ActionNone
+type Board interface {
+	// Dimensions returns the area covered by the board
+	Dimensions() Dimensions
+	// Cell returns the cell at the given point, ok is false if it has never been visited or it is out of the board
+	Cell(p Point) (cell Cell, ok bool)
+	// SetCell stores the cell at cell.Point, a cell with ActionNone clears it.
+	// It fails with ErrOutOfBounds if the point is out of the board
+	SetCell(cell Cell) error
+	// Each calls fn for every visited cell until fn returns false
+	Each(fn func(cell Cell) bool)
+	// Empty returns a board of the same kind with the given dimensions and no visited cells
+	Empty(dimensions Dimensions) Board
+}
+
+// stepsBoard is implemented by boards that store step indexes and need to know the rule to build the cells
+type stepsBoard interface {
+	SetSteps(steps Steps)
+}
+
+// DenseBoard stores every cell of the Dimensions in a slice.
+// It is the fastest board when most of the area is visited
+type DenseBoard struct {
+	dimensions Dimensions
+	cells      []Cell
+}
+
+// NewDenseBoard creates an empty DenseBoard
+func NewDenseBoard(dimensions Dimensions) *DenseBoard {
+	return &DenseBoard{
+		dimensions: dimensions,
+		cells:      make([]Cell, dimensions.Size, dimensions.Size),
+	}
+}
+
+// Dimensions returns the area covered by the board
+func (board *DenseBoard) Dimensions() Dimensions {
+	return board.dimensions
+}
+
+// Cell returns the cell at the given point
+func (board *DenseBoard) Cell(p Point) (Cell, bool) {
+	if !board.dimensions.Contains(p) {
+		return Cell{}, false
+	}
+	cell := board.cells[board.dimensions.indexOf(p)]
+	return cell, cell.Step.Action != ActionNone
+}
+
+// SetCell stores the cell at cell.Point
+func (board *DenseBoard) SetCell(cell Cell) error {
+	if !board.dimensions.Contains(cell.Point) {
+		return ErrOutOfBounds
+	}
+	index := board.dimensions.indexOf(cell.Point)
+	if cell.Step.Action == ActionNone {
+		cell = Cell{}
+	}
+	board.cells[index] = cell
+	return nil
+}
+
+// Each calls fn for every visited cell, from the bottom left corner row by row
+func (board *DenseBoard) Each(fn func(cell Cell) bool) {
+	for i := range board.cells {
+		if board.cells[i].Step.Action == ActionNone {
+			continue
+		}
+		if !fn(board.cells[i]) {
+			return
+		}
+	}
+}
+
+// Empty returns an empty DenseBoard with the given dimensions
+func (board *DenseBoard) Empty(dimensions Dimensions) Board {
+	return NewDenseBoard(dimensions)
+}
+
+// SparseBoard only stores the visited cells in a map.
+// It allows huge dimensions when the ant only visits a small part of them
+type SparseBoard struct {
+	dimensions Dimensions
+	cells      map[Point]Cell
+}
+
+// NewSparseBoard creates an empty SparseBoard
+func NewSparseBoard(dimensions Dimensions) *SparseBoard {
+	return &SparseBoard{
+		dimensions: dimensions,
+		cells:      map[Point]Cell{},
+	}
+}
+
+// Dimensions returns the area covered by the board
+func (board *SparseBoard) Dimensions() Dimensions {
+	return board.dimensions
+}
+
+// Cell returns the cell at the given point
+func (board *SparseBoard) Cell(p Point) (Cell, bool) {
+	cell, ok := board.cells[p]
+	return cell, ok
+}
+
+// SetCell stores the cell at cell.Point
+func (board *SparseBoard) SetCell(cell Cell) error {
+	if !board.dimensions.Contains(cell.Point) {
+		return ErrOutOfBounds
+	}
+	if cell.Step.Action == ActionNone {
+		delete(board.cells, cell.Point)
+		return nil
+	}
+	board.cells[cell.Point] = cell
+	return nil
+}
+
+// Each calls fn for every visited cell in no particular order
+func (board *SparseBoard) Each(fn func(cell Cell) bool) {
+	for _, cell := range board.cells {
+		if !fn(cell) {
+			return
+		}
+	}
+}
+
+// Empty returns an empty SparseBoard with the given dimensions
+func (board *SparseBoard) Empty(dimensions Dimensions) Board {
+	return NewSparseBoard(dimensions)
+}
+
+// copyBoard copies every visited cell of src into dst, cells out of dst are ignored
+func copyBoard(dst, src Board) {
+	denseDst, dstOk := dst.(*DenseBoard)
+	denseSrc, srcOk := src.(*DenseBoard)
+	if dstOk && srcOk && denseDst.dimensions == denseSrc.dimensions {
+		copy(denseDst.cells, denseSrc.cells)
+		return
+	}
+
+	dimensions := dst.Dimensions()
+	src.Each(func(cell Cell) bool {
+		if dimensions.Contains(cell.Point) {
+			dst.SetCell(cell)
+		}
+		return true
+	})
+}
+
+// CompactBoard stores a single byte per cell with the index of its step.
+// It supports rules up to MaxCompactSteps steps and uses a fraction of the memory of a DenseBoard
+type CompactBoard struct {
+	dimensions Dimensions
+	steps      Steps
+	indexes    []uint8
+}
+
+// MaxCompactSteps is the longest rule that can be stored in a CompactBoard
+const MaxCompactSteps = 255
+
+// NewCompactBoard creates an empty CompactBoard for the given steps
+func NewCompactBoard(dimensions Dimensions, steps Steps) *CompactBoard {
+	return &CompactBoard{
+		dimensions: dimensions,
+		steps:      numerated(steps),
+		indexes:    make([]uint8, dimensions.Size, dimensions.Size),
+	}
+}
+
+// Dimensions returns the area covered by the board
+func (board *CompactBoard) Dimensions() Dimensions {
+	return board.dimensions
+}
+
+// Cell returns the cell at the given point
+func (board *CompactBoard) Cell(p Point) (Cell, bool) {
+	if !board.dimensions.Contains(p) {
+		return Cell{}, false
+	}
+	index := board.indexes[board.dimensions.indexOf(p)]
+	if index == 0 {
+		return Cell{}, false
+	}
+	return Cell{
+		Point: p,
+		Step:  board.steps[index-1],
+	}, true
+}
+
+// SetCell stores the step index of the cell, it fails if the index does not fit in a byte
+func (board *CompactBoard) SetCell(cell Cell) error {
+	if !board.dimensions.Contains(cell.Point) {
+		return ErrOutOfBounds
+	}
+	var index uint8
+	if cell.Step.Action != ActionNone {
+		if cell.Step.Index >= MaxCompactSteps || cell.Step.Index >= len(board.steps) {
+			return fmt.Errorf("step index %d does not fit in a CompactBoard", cell.Step.Index)
+		}
+		index = uint8(cell.Step.Index + 1)
+	}
+	board.indexes[board.dimensions.indexOf(cell.Point)] = index
+	return nil
+}
+
+// Each calls fn for every visited cell, from the bottom left corner row by row
+func (board *CompactBoard) Each(fn func(cell Cell) bool) {
+	for i, index := range board.indexes {
+		if index == 0 {
+			continue
+		}
+		cell := Cell{
+			Point: board.dimensions.pointOf(i),
+			Step:  board.steps[index-1],
+		}
+		if !fn(cell) {
+			return
+		}
+	}
+}
+
+// Empty returns an empty CompactBoard with the given dimensions and the same steps
+func (board *CompactBoard) Empty(dimensions Dimensions) Board {
+	return NewCompactBoard(dimensions, board.steps)
+}
+
+// SetSteps replaces the steps used to build the cells, it is called by Ant.SetSteps
+func (board *CompactBoard) SetSteps(steps Steps) {
+	board.steps = numerated(steps)
+}
+
+// numerated returns a numerated copy of the steps
+func numerated(steps Steps) Steps {
+	out := make(Steps, len(steps))
+	copy(out, steps)
+	out.Numerate()
+	return out
+}
@@ -0,0 +1,126 @@
+package langton
+
+import "testing"
+
+func TestBoard_SparseMatchesDense(t *testing.T) {
+	dense := NewAntOnBoard(NewDenseBoard(NewBoard(15)), StepsAwesome...)
+	sparse := NewAntOnBoard(NewSparseBoard(NewBoard(15)), StepsFromString(StepsAwesome.String())...)
+	for {
+		_, denseErr := dense.Next()
+		_, sparseErr := sparse.Next()
+		if (denseErr != nil) != (sparseErr != nil) {
+			t.Fatalf("dense error = %v, sparse error = %v", denseErr, sparseErr)
+		}
+		if denseErr != nil {
+			break
+		}
+	}
+	if dense.String() != sparse.String() {
+		t.Errorf("sparse board = \n%s, want \n%s", sparse, dense)
+	}
+
+	if err := sparse.Grow(NewBoard(20)); err != nil {
+		t.Fatalf("Ant.Grow() error = %v", err)
+	}
+	if err := dense.Grow(NewBoard(20)); err != nil {
+		t.Fatalf("Ant.Grow() error = %v", err)
+	}
+	dense.NextN(1000)
+	sparse.NextN(1000)
+	if dense.String() != sparse.String() {
+		t.Errorf("sparse board after grow = \n%s, want \n%s", sparse, dense)
+	}
+}
+
+func TestBoard_SetCell(t *testing.T) {
+	boards := map[string]Board{
+		"dense":  NewDenseBoard(NewBoard(1)),
+		"sparse": NewSparseBoard(NewBoard(1)),
+	}
+	for name, board := range boards {
+		t.Run(name, func(t *testing.T) {
+			cell := Cell{
+				Point: Point{X: 1, Y: -1},
+				Step:  Step{Index: 1, Action: ActionTurnRight},
+			}
+			if err := board.SetCell(cell); err != nil {
+				t.Fatalf("Board.SetCell() error = %v", err)
+			}
+			if got, ok := board.Cell(cell.Point); !ok || got != cell {
+				t.Errorf("Board.Cell() = %v, %v, want %v", got, ok, cell)
+			}
+			if err := board.SetCell(Cell{Point: Point{X: 2}}); err != ErrOutOfBounds {
+				t.Errorf("Board.SetCell() error = %v, want %v", err, ErrOutOfBounds)
+			}
+
+			visited := 0
+			board.Each(func(cell Cell) bool {
+				visited++
+				return true
+			})
+			if visited != 1 {
+				t.Errorf("Board.Each() visited %d cells, want %d", visited, 1)
+			}
+
+			board.SetCell(Cell{Point: cell.Point})
+			if _, ok := board.Cell(cell.Point); ok {
+				t.Errorf("Board.SetCell() with ActionNone must clear the cell")
+			}
+		})
+	}
+}
+
+func TestAnt_Visited(t *testing.T) {
+	ant := NewAnt(NewBoard(50), StepsAwesome...)
+	ant.NextN(5000)
+
+	want := Dimensions{}
+	first := true
+	ant.Board.Each(func(cell Cell) bool {
+		if first {
+			want = NewDimensions(cell.X, cell.Y, cell.X, cell.Y)
+			first = false
+		}
+		want = want.extend(cell.Point)
+		return true
+	})
+	if got := ant.Visited(); got != want {
+		t.Errorf("Ant.Visited() = %v %v, want %v %v", got.BottomLeft, got.TopRight, want.BottomLeft, want.TopRight)
+	}
+}
+
+func TestAnt_Crop(t *testing.T) {
+	ant := NewAnt(NewBoard(50), StepsAwesome...)
+	ant.NextN(5000)
+	visited := ant.Visited()
+	before := ant.StringMargin(0)
+
+	if err := ant.Crop(2); err != nil {
+		t.Fatalf("Ant.Crop() error = %v", err)
+	}
+	want := visited.Expand(2)
+	if got := ant.Dimensions(); got != want {
+		t.Errorf("Ant.Dimensions() = %v, want %v", got.String(), want.String())
+	}
+	if err := ant.Grow(NewBoard(50)); err != nil {
+		t.Fatalf("Ant.Grow() error = %v", err)
+	}
+	if got := ant.StringMargin(0); got != before {
+		t.Errorf("Ant after Crop and Grow = \n%s, want \n%s", got, before)
+	}
+
+	if err := ant.Crop(100); err != nil {
+		t.Fatalf("Ant.Crop() error = %v", err)
+	}
+	if got := ant.Dimensions(); got != NewBoard(50) {
+		t.Errorf("Ant.Crop() with a big margin = %v, want %v", got.String(), "101x101")
+	}
+}
+
+func TestAnt_GrowSmallerThanVisited(t *testing.T) {
+	ant := NewAnt(NewBoard(50), StepsAwesome...)
+	ant.NextN(5000)
+	if err := ant.Grow(NewDimensions(0, 0, 200, 200)); err == nil {
+		t.Errorf("Ant.Grow() error = nil, want error")
+	}
+}
@@ -0,0 +1,620 @@
+package langton
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// checkpointMagic identifies the binary checkpoint format
+const checkpointMagic = "ANTC"
+
+// checkpointVersion is increased every time the format changes
+const checkpointVersion uint8 = 2
+
+// checkpointPrefix and checkpointExtension build the checkpoint file names, the total steps are padded so names sort by step
+const (
+	checkpointPrefix    = "checkpoint-"
+	checkpointExtension = ".antc"
+)
+
+// maxCheckpointSide and maxCheckpointCells bound the boards read from a checkpoint before anything is allocated,
+// the cells only limit the boards that allocate the whole area
+const (
+	maxCheckpointSide  = 1 << 30
+	maxCheckpointCells = 1 << 32
+)
+
+// DefaultCheckpointKeep is the number of checkpoints kept by a Checkpointer with Keep 0
+const DefaultCheckpointKeep = 3
+
+// Board types stored in a checkpoint
+const (
+	checkpointDense uint8 = iota
+	checkpointSparse
+	checkpointCompact
+)
+
+var (
+	ErrInvalidCheckpoint     = errors.New("Invalid checkpoint")
+	ErrUnsupportedCheckpoint = errors.New("The ant can not be checkpointed")
+	ErrNoCheckpoint          = errors.New("No valid checkpoint found")
+)
+
+// WriteCheckpoint writes the whole state of the ant, including the board, the random source, the turmite, the sensing rule,
+// the noise and the schedule, so the run continues exactly as if it was never stopped.
+// The output is compressed with gzip, its checksum makes ReadCheckpoint fail on truncated or corrupted files.
+// Custom Board, Noise and ColorMapping implementations can not be saved
+func WriteCheckpoint(w io.Writer, ant *Ant) error {
+	kind, err := checkpointBoard(ant.Board)
+	if err != nil {
+		return err
+	}
+	var (
+		hasNoise    bool
+		noiseEvery  int64
+		noiseRadius int64
+	)
+	switch noise := ant.noise.(type) {
+	case nil:
+	case FlipNoise:
+		hasNoise, noiseEvery, noiseRadius = true, noise.Every, noise.Radius
+	case *FlipNoise:
+		hasNoise, noiseEvery, noiseRadius = true, noise.Every, noise.Radius
+	default:
+		return fmt.Errorf("%w: unknown noise %T", ErrUnsupportedCheckpoint, noise)
+	}
+	var (
+		schedule   string
+		loop       bool
+		phase      int64
+		nextSwitch int64
+	)
+	if ant.schedule != nil {
+		if ant.schedule.schedule.Mapping != nil {
+			return fmt.Errorf("%w: schedules with a custom mapping", ErrUnsupportedCheckpoint)
+		}
+		schedule = ant.schedule.schedule.String()
+		loop = ant.schedule.schedule.Loop
+		phase = int64(ant.schedule.phase)
+		nextSwitch = ant.schedule.nextSwitch
+	}
+	var (
+		turmite      string
+		turmiteState int64
+	)
+	if ant.turmite != nil {
+		turmite = ant.turmite.turmite.String()
+		turmiteState = int64(ant.turmite.state)
+	}
+	sensing := ""
+	if ant.sensing != nil {
+		sensing = ant.sensing.String()
+	}
+
+	compressed := gzip.NewWriter(w)
+	encoder := &checkpointEncoder{w: bufio.NewWriter(compressed)}
+	encoder.value([]byte(checkpointMagic))
+	encoder.value(checkpointVersion)
+	encoder.string(Steps(ant.steps).String())
+	encoder.value(kind)
+	dimensions := ant.Dimensions()
+	encoder.value(dimensions.BottomLeft)
+	encoder.value(dimensions.TopRight)
+	visited := ant.visited
+	encoder.value(visited.BottomLeft)
+	encoder.value(visited.TopRight)
+	// only the stored cells are written, the count followed by the position relative to the visited corner and the step index
+	var cells uint64
+	ant.Board.Each(func(cell Cell) bool {
+		cells++
+		return true
+	})
+	encoder.uvarint(cells)
+	ant.Board.Each(func(cell Cell) bool {
+		encoder.uvarint(uint64(cell.X - visited.BottomLeft.X))
+		encoder.uvarint(uint64(cell.Y - visited.BottomLeft.Y))
+		encoder.uvarint(uint64(cell.Step.Index))
+		return encoder.err == nil
+	})
+	encoder.value(ant.Position.Point)
+	encoder.value(int64(ant.Direction))
+	encoder.value(ant.totalSteps)
+	encoder.value(ant.stuck)
+	encoder.value(int32(ant.lastAction))
+	encoder.value(ant.random.state)
+	encoder.string(turmite)
+	encoder.value(turmiteState)
+	encoder.string(sensing)
+	encoder.value(hasNoise)
+	encoder.value(noiseEvery)
+	encoder.value(noiseRadius)
+	encoder.string(schedule)
+	encoder.value(loop)
+	encoder.value(phase)
+	encoder.value(nextSwitch)
+	if encoder.err != nil {
+		return encoder.err
+	}
+	err = encoder.w.Flush()
+	if err != nil {
+		return err
+	}
+	return compressed.Close()
+}
+
+// checkpointBoard returns the type of board stored in the checkpoint
+func checkpointBoard(board Board) (uint8, error) {
+	switch board.(type) {
+	case *DenseBoard:
+		return checkpointDense, nil
+	case *SparseBoard:
+		return checkpointSparse, nil
+	case *CompactBoard:
+		return checkpointCompact, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown board %T", ErrUnsupportedCheckpoint, board)
+	}
+}
+
+// checkpointEncoder writes little endian values keeping the first error
+type checkpointEncoder struct {
+	w   *bufio.Writer
+	err error
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (encoder *checkpointEncoder) value(v interface{}) {
+	if encoder.err == nil {
+		encoder.err = binary.Write(encoder.w, binary.LittleEndian, v)
+	}
+}
+
+func (encoder *checkpointEncoder) string(s string) {
+	encoder.value(int64(len(s)))
+	encoder.value([]byte(s))
+}
+
+func (encoder *checkpointEncoder) uvarint(v uint64) {
+	if encoder.err == nil {
+		n := binary.PutUvarint(encoder.buf[:], v)
+		_, encoder.err = encoder.w.Write(encoder.buf[:n])
+	}
+}
+
+// checkpointDecoder reads the values written by checkpointEncoder keeping the first error
+type checkpointDecoder struct {
+	r   *bufio.Reader
+	err error
+}
+
+func (decoder *checkpointDecoder) value(v interface{}) {
+	if decoder.err == nil {
+		decoder.err = binary.Read(decoder.r, binary.LittleEndian, v)
+	}
+}
+
+func (decoder *checkpointDecoder) string() string {
+	var length int64
+	decoder.value(&length)
+	if decoder.err != nil {
+		return ""
+	}
+	if length < 0 || length > 1<<20 {
+		decoder.err = fmt.Errorf("%w: string length %d", ErrInvalidCheckpoint, length)
+		return ""
+	}
+	s := make([]byte, length)
+	decoder.value(s)
+	return string(s)
+}
+
+func (decoder *checkpointDecoder) uvarint() uint64 {
+	if decoder.err != nil {
+		return 0
+	}
+	var v uint64
+	v, decoder.err = binary.ReadUvarint(decoder.r)
+	return v
+}
+
+// ReadCheckpoint restores an ant written with WriteCheckpoint
+func ReadCheckpoint(r io.Reader) (*Ant, error) {
+	compressed, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+	}
+	decoder := &checkpointDecoder{r: bufio.NewReader(compressed)}
+
+	magic := make([]byte, len(checkpointMagic))
+	var version uint8
+	decoder.value(magic)
+	decoder.value(&version)
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+	if string(magic) != checkpointMagic {
+		return nil, ErrInvalidCheckpoint
+	}
+	if version != checkpointVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidCheckpoint, version)
+	}
+
+	steps, err := ParseSteps(decoder.string())
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+	}
+	steps.Numerate()
+	var (
+		kind                                       uint8
+		bottomLeft, topRight, visitedBL, visitedTR Point
+	)
+	for _, field := range []interface{}{&kind, &bottomLeft, &topRight, &visitedBL, &visitedTR} {
+		decoder.value(field)
+	}
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+	for _, p := range []Point{bottomLeft, topRight, visitedBL, visitedTR} {
+		if p.X < -maxCheckpointSide || p.X > maxCheckpointSide || p.Y < -maxCheckpointSide || p.Y > maxCheckpointSide {
+			return nil, fmt.Errorf("%w: corner %s out of range", ErrInvalidCheckpoint, p)
+		}
+	}
+	dimensions := NewDimensions(bottomLeft.X, bottomLeft.Y, topRight.X, topRight.Y)
+	visited := NewDimensions(visitedBL.X, visitedBL.Y, visitedTR.X, visitedTR.Y)
+	if dimensions.width <= 0 || dimensions.height <= 0 || visited.width <= 0 || visited.height <= 0 ||
+		!dimensions.ContainsDimensions(visited) {
+		return nil, fmt.Errorf("%w: visited cells %s %s out of the board", ErrInvalidCheckpoint, visitedBL, visitedTR)
+	}
+	if kind != checkpointSparse && dimensions.Size > maxCheckpointCells {
+		return nil, fmt.Errorf("%w: board of %s cells is too big", ErrInvalidCheckpoint, &dimensions)
+	}
+	if kind == checkpointCompact && len(steps) > MaxCompactSteps {
+		return nil, fmt.Errorf("%w: too many steps for a CompactBoard", ErrInvalidCheckpoint)
+	}
+
+	// the cells are kept until the checksum is verified, the board is only allocated for a valid checkpoint
+	count := decoder.uvarint()
+	if decoder.err == nil && count > uint64(visited.Size) {
+		return nil, fmt.Errorf("%w: %d cells in %s", ErrInvalidCheckpoint, count, &visited)
+	}
+	cells := []Cell{}
+	for i := uint64(0); i < count && decoder.err == nil; i++ {
+		x, y, index := decoder.uvarint(), decoder.uvarint(), decoder.uvarint()
+		if decoder.err != nil {
+			break
+		}
+		p := Point{X: visited.BottomLeft.X + int64(x), Y: visited.BottomLeft.Y + int64(y)}
+		if x >= uint64(visited.width) || y >= uint64(visited.height) {
+			return nil, fmt.Errorf("%w: cell %d, %d out of the visited cells", ErrInvalidCheckpoint, x, y)
+		}
+		if index >= uint64(len(steps)) {
+			return nil, fmt.Errorf("%w: step index %d out of the rule", ErrInvalidCheckpoint, index)
+		}
+		cells = append(cells, Cell{
+			Point: p,
+			Step:  steps[index],
+		})
+	}
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+
+	var (
+		position     Point
+		direction    int64
+		totalSteps   int64
+		stuck        bool
+		lastAction   int32
+		randomState  uint64
+		turmiteState int64
+		hasNoise     bool
+		noiseEvery   int64
+		noiseRadius  int64
+		loop         bool
+		phase        int64
+		nextSwitch   int64
+	)
+	for _, field := range []interface{}{&position, &direction, &totalSteps, &stuck, &lastAction, &randomState} {
+		decoder.value(field)
+	}
+	turmite := decoder.string()
+	decoder.value(&turmiteState)
+	sensing := decoder.string()
+	for _, field := range []interface{}{&hasNoise, &noiseEvery, &noiseRadius} {
+		decoder.value(field)
+	}
+	schedule := decoder.string()
+	for _, field := range []interface{}{&loop, &phase, &nextSwitch} {
+		decoder.value(field)
+	}
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+	// reading until the end verifies the gzip checksum
+	_, err = io.Copy(ioutil.Discard, decoder.r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+	}
+
+	if direction < 0 || direction >= int64(DirectionInvalid) || totalSteps < 0 {
+		return nil, ErrInvalidCheckpoint
+	}
+	var board Board
+	switch kind {
+	case checkpointDense:
+		board = NewDenseBoard(dimensions)
+	case checkpointSparse:
+		board = NewSparseBoard(dimensions)
+	case checkpointCompact:
+		board = NewCompactBoard(dimensions, steps)
+	default:
+		return nil, fmt.Errorf("%w: unknown board %d", ErrInvalidCheckpoint, kind)
+	}
+	for _, cell := range cells {
+		board.SetCell(cell)
+	}
+	if _, ok := board.Cell(position); !ok {
+		return nil, fmt.Errorf("%w: the ant is on a cell %s that has not been visited", ErrInvalidCheckpoint, position)
+	}
+	ant, err := newAntAt(board, position, steps...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+	}
+	ant.visited = visited
+	ant.Direction = Direction(direction)
+	ant.totalSteps = totalSteps
+	ant.stuck = stuck
+	ant.lastAction = Action(lastAction)
+	ant.random.state = randomState
+
+	if turmite != "" {
+		antTurmite, err := ParseTurmite(turmite)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+		}
+		err = ant.SetTurmite(antTurmite)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+		}
+		if turmiteState < 0 || turmiteState >= int64(antTurmite.States()) {
+			return nil, fmt.Errorf("%w: turmite state %d does not exist", ErrInvalidCheckpoint, turmiteState)
+		}
+		ant.turmite.state = int(turmiteState)
+	}
+	if sensing != "" {
+		rule, err := ParseSensingRule(sensing)
+		if err == nil {
+			err = ant.SetSensingRule(rule)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+		}
+	}
+	if hasNoise {
+		err = ant.SetNoise(FlipNoise{
+			Every:  noiseEvery,
+			Radius: noiseRadius,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+		}
+	}
+	if schedule != "" {
+		antSchedule, err := ParseSchedule(schedule)
+		if err == nil {
+			antSchedule.Loop = loop
+			err = antSchedule.Validate()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCheckpoint, err)
+		}
+		if phase < 0 || phase >= int64(len(antSchedule.Phases)) {
+			return nil, fmt.Errorf("%w: schedule phase %d does not exist", ErrInvalidCheckpoint, phase)
+		}
+		if ant.turmite != nil {
+			return nil, fmt.Errorf("%w: a turmite can not follow a schedule", ErrInvalidCheckpoint)
+		}
+		if ant.sensing != nil && antSchedule.MaxColors() > len(sensingDigits) {
+			return nil, fmt.Errorf("%w: the schedule has too many colours for the sensing rule", ErrInvalidCheckpoint)
+		}
+		// the board already has the steps of the current phase, SetSchedule would translate it again
+		ant.schedule = &scheduleState{
+			schedule:   antSchedule,
+			phase:      int(phase),
+			nextSwitch: nextSwitch,
+		}
+	}
+	return ant, nil
+}
+
+// WriteCheckpointFile writes the checkpoint to a temporary file in the same directory and renames it to path once it is on disk,
+// so path always holds a complete checkpoint even if the process dies while writing
+func WriteCheckpointFile(path string, ant *Ant) (err error) {
+	dir := filepath.Dir(path)
+	file, err := ioutil.TempFile(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+	err = WriteCheckpoint(file, ant)
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return err
+	}
+	// the rename is only durable once the directory is synced, not every platform supports it
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// ReadCheckpointFile restores an ant from a checkpoint file
+func ReadCheckpointFile(path string) (*Ant, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadCheckpoint(file)
+}
+
+// CheckpointName returns the name of the checkpoint file of an ant that has performed the given steps
+func CheckpointName(steps int64) string {
+	return fmt.Sprintf("%s%020d%s", checkpointPrefix, steps, checkpointExtension)
+}
+
+// checkpointFiles returns the checkpoint files in dir from the oldest to the newest
+func checkpointFiles(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, checkpointPrefix) || !strings.HasSuffix(name, checkpointExtension) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Resume restores the ant from the newest valid checkpoint in dir, damaged checkpoints are skipped.
+// It fails with ErrNoCheckpoint if there is none
+func Resume(dir string) (*Ant, error) {
+	files, err := checkpointFiles(dir)
+	if os.IsNotExist(err) {
+		return nil, ErrNoCheckpoint
+	}
+	if err != nil {
+		return nil, err
+	}
+	var last error
+	for i := len(files) - 1; i >= 0; i-- {
+		ant, err := ReadCheckpointFile(files[i])
+		if err == nil {
+			return ant, nil
+		}
+		last = fmt.Errorf("%s: %w", files[i], err)
+	}
+	if last != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoCheckpoint, last)
+	}
+	return nil, ErrNoCheckpoint
+}
+
+// Checkpointer saves checkpoints of a long run to a directory every number of steps or every time interval,
+// whatever comes first. Call Check between steps, it only writes when a checkpoint is due
+type Checkpointer struct {
+	// Dir is the directory where the checkpoints are written, it must exist
+	Dir string
+	// Every is the number of steps between checkpoints, 0 disables it
+	Every int64
+	// Interval is the time between checkpoints, 0 disables it
+	Interval time.Duration
+	// Keep is the number of checkpoints kept in Dir, DefaultCheckpointKeep if 0 and all of them if negative.
+	// Keeping more than one is a fallback if the newest is damaged
+	Keep int
+
+	started   bool
+	lastSteps int64
+	lastTime  time.Time
+}
+
+// Start counts the steps and time to the next checkpoint from the current state of the ant
+func (checkpointer *Checkpointer) Start(ant *Ant) {
+	checkpointer.mark(ant)
+}
+
+// Due returns true if a checkpoint of the ant must be written.
+// The first call starts counting steps and time from the current state of the ant if Start was not called
+func (checkpointer *Checkpointer) Due(ant *Ant) bool {
+	if !checkpointer.started {
+		checkpointer.mark(ant)
+		return false
+	}
+	if checkpointer.Every > 0 && ant.TotalSteps()-checkpointer.lastSteps >= checkpointer.Every {
+		return true
+	}
+	return checkpointer.Interval > 0 && time.Since(checkpointer.lastTime) >= checkpointer.Interval
+}
+
+// Check saves a checkpoint if it is due, saved is true if it was written
+func (checkpointer *Checkpointer) Check(ant *Ant) (saved bool, err error) {
+	if !checkpointer.Due(ant) {
+		return false, nil
+	}
+	_, err = checkpointer.Save(ant)
+	return err == nil, err
+}
+
+// Save writes a checkpoint of the ant and removes the old ones, it returns the path of the new checkpoint
+func (checkpointer *Checkpointer) Save(ant *Ant) (string, error) {
+	path := filepath.Join(checkpointer.Dir, CheckpointName(ant.TotalSteps()))
+	err := WriteCheckpointFile(path, ant)
+	if err != nil {
+		return "", err
+	}
+	checkpointer.mark(ant)
+	return path, checkpointer.prune()
+}
+
+func (checkpointer *Checkpointer) mark(ant *Ant) {
+	checkpointer.started = true
+	checkpointer.lastSteps = ant.TotalSteps()
+	checkpointer.lastTime = time.Now()
+}
+
+// prune removes the oldest checkpoints beyond Keep
+func (checkpointer *Checkpointer) prune() error {
+	keep := checkpointer.Keep
+	if keep == 0 {
+		keep = DefaultCheckpointKeep
+	}
+	if keep < 0 {
+		return nil
+	}
+	files, err := checkpointFiles(checkpointer.Dir)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(files)-keep; i++ {
+		err = os.Remove(files[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package langton
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint_Resume(t *testing.T) {
+	newTurmite := func() *Ant {
+		turmite, err := ParseTurmite("{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+		if err != nil {
+			t.Fatalf("ParseTurmite() error = %v", err)
+		}
+		ant := NewAntOnBoard(NewSparseBoard(NewBoard(40)), StepsFromString("LL")...)
+		err = ant.SetTurmite(turmite)
+		if err != nil {
+			t.Fatalf("SetTurmite() error = %v", err)
+		}
+		return ant
+	}
+	tests := []struct {
+		name string
+		ant  func() *Ant
+	}{
+		{
+			name: "dense",
+			ant: func() *Ant {
+				return NewAntFromString(NewBoard(40), "RLLLLRRRLLL")
+			},
+		},
+		{
+			name: "compact stochastic with noise",
+			ant: func() *Ant {
+				steps := StepsFromString("[L0.9R]RLR")
+				ant := NewAntOnBoard(NewCompactBoard(NewBoard(40), steps), steps...)
+				ant.Seed(7)
+				ant.SetNoise(FlipNoise{Every: 10, Radius: 3})
+				return ant
+			},
+		},
+		{
+			name: "sparse turmite",
+			ant:  newTurmite,
+		},
+		{
+			name: "schedule and sensing",
+			ant: func() *Ant {
+				ant := NewAntFromString(NewBoard(40), "LR")
+				schedule, err := ParseSchedule("LR:150,LLRR:150")
+				if err != nil {
+					t.Fatalf("ParseSchedule() error = %v", err)
+				}
+				schedule.Loop = true
+				err = ant.SetSchedule(schedule)
+				if err != nil {
+					t.Fatalf("SetSchedule() error = %v", err)
+				}
+				rule, err := ParseSensingRule("ahead *1:S")
+				if err != nil {
+					t.Fatalf("ParseSensingRule() error = %v", err)
+				}
+				err = ant.SetSensingRule(rule)
+				if err != nil {
+					t.Fatalf("SetSensingRule() error = %v", err)
+				}
+				return ant
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.ant()
+			want.NextN(200)
+
+			buf := &bytes.Buffer{}
+			err := WriteCheckpoint(buf, want)
+			if err != nil {
+				t.Fatalf("WriteCheckpoint() error = %v", err)
+			}
+			got, err := ReadCheckpoint(buf)
+			if err != nil {
+				t.Fatalf("ReadCheckpoint() error = %v", err)
+			}
+			if !got.Equal(want) || got.Hash() != want.Hash() || got.Visited() != want.Visited() {
+				t.Fatalf("ReadCheckpoint() differs in %v", got.Diff(want))
+			}
+
+			// both ants must take the same decisions from now on
+			for i := 0; i < 300; i++ {
+				_, wantErr := want.Next()
+				_, gotErr := got.Next()
+				if (gotErr != nil) != (wantErr != nil) {
+					t.Fatalf("Next() error = %v, want %v", gotErr, wantErr)
+				}
+			}
+			if got.TotalSteps() != want.TotalSteps() || got.TurmiteState() != want.TurmiteState() || got.Phase() != want.Phase() {
+				t.Errorf("resumed ant is at step %d state %d phase %d, want %d %d %d",
+					got.TotalSteps(), got.TurmiteState(), got.Phase(),
+					want.TotalSteps(), want.TurmiteState(), want.Phase())
+			}
+			if !got.Equal(want) {
+				t.Errorf("resumed ant differs in %v", got.Diff(want))
+			}
+		})
+	}
+}
+
+func TestCheckpoint_Corrupted(t *testing.T) {
+	ant := NewAntFromString(NewBoard(20), "RLR")
+	ant.NextN(500)
+	buf := &bytes.Buffer{}
+	err := WriteCheckpoint(buf, ant)
+	if err != nil {
+		t.Fatalf("WriteCheckpoint() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	_, err = ReadCheckpoint(bytes.NewReader(data[:len(data)-10]))
+	if err == nil {
+		t.Errorf("ReadCheckpoint() of a truncated checkpoint error = nil")
+	}
+
+	damaged := append([]byte{}, data...)
+	damaged[len(damaged)-6] ^= 0xff
+	_, err = ReadCheckpoint(bytes.NewReader(damaged))
+	if err == nil {
+		t.Errorf("ReadCheckpoint() of a damaged checkpoint error = nil")
+	}
+}
+
+func TestCheckpoint_Size(t *testing.T) {
+	huge := NewDimensions(-1<<29, -1<<29, 1<<29, 1<<29)
+	ant := NewAntOnBoard(NewSparseBoard(huge), StepsFromString("RL")...)
+	ant.NextN(1000)
+	buf := &bytes.Buffer{}
+	err := WriteCheckpoint(buf, ant)
+	if err != nil {
+		t.Fatalf("WriteCheckpoint() error = %v", err)
+	}
+	// the size follows the visited cells, not the board
+	if buf.Len() > 4096 {
+		t.Errorf("WriteCheckpoint() wrote %d bytes, want at most 4096", buf.Len())
+	}
+	got, err := ReadCheckpoint(buf)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint() error = %v", err)
+	}
+	if !got.Equal(ant) {
+		t.Errorf("ReadCheckpoint() differs in %v", got.Diff(ant))
+	}
+
+	// a dense board that big is refused before it is allocated
+	buf.Reset()
+	compressed := gzip.NewWriter(buf)
+	encoder := &checkpointEncoder{w: bufio.NewWriter(compressed)}
+	encoder.value([]byte(checkpointMagic))
+	encoder.value(checkpointVersion)
+	encoder.string("RL")
+	encoder.value(checkpointDense)
+	encoder.value(huge.BottomLeft)
+	encoder.value(huge.TopRight)
+	encoder.value(Point{})
+	encoder.value(Point{})
+	encoder.w.Flush()
+	compressed.Close()
+	_, err = ReadCheckpoint(buf)
+	if !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("ReadCheckpoint() of a huge dense board error = %v, want %v", err, ErrInvalidCheckpoint)
+	}
+}
+
+func TestCheckpointer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	_, err = Resume(dir)
+	if !errors.Is(err, ErrNoCheckpoint) {
+		t.Fatalf("Resume() of an empty directory error = %v, want %v", err, ErrNoCheckpoint)
+	}
+
+	ant := NewAntFromString(NewBoard(50), "RLLLLRRRLLL")
+	checkpointer := &Checkpointer{
+		Dir:   dir,
+		Every: 100,
+		Keep:  2,
+	}
+	saves := 0
+	for i := 0; i < 1000; i++ {
+		ant.Next()
+		saved, err := checkpointer.Check(ant)
+		if err != nil {
+			t.Fatalf("Checkpointer.Check() error = %v", err)
+		}
+		if saved {
+			saves++
+		}
+	}
+	// the first check only starts counting
+	if saves != 9 {
+		t.Errorf("Checkpointer.Check() saved %d checkpoints, want 9", saves)
+	}
+	_, err = checkpointer.Save(ant)
+	if err != nil {
+		t.Fatalf("Checkpointer.Save() error = %v", err)
+	}
+	files, err := checkpointFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Checkpointer kept %d checkpoints, want 2", len(files))
+	}
+
+	resumed, err := Resume(dir)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if resumed.TotalSteps() != 1000 {
+		t.Errorf("Resume() TotalSteps() = %d, want 1000", resumed.TotalSteps())
+	}
+
+	// a damaged newest checkpoint falls back to the previous one
+	err = ioutil.WriteFile(filepath.Join(dir, CheckpointName(1100)), []byte("broken"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err = Resume(dir)
+	if err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if resumed.TotalSteps() != 1000 {
+		t.Errorf("Resume() TotalSteps() = %d, want 1000", resumed.TotalSteps())
+	}
+}
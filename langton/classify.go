@@ -0,0 +1,349 @@
+package langton
+
+import (
+	"errors"
+	"math"
+)
+
+// Default values of a Classifier with zero fields
+const (
+	DefaultClassifierBudget  = 1000000
+	DefaultClassifierSize    = 500
+	DefaultMaxHighwayPeriod  = 2000
+	DefaultClassifierSamples = 32
+)
+
+// Tags produced by the classifier, they use the vocabulary of patterns.txt
+const (
+	TagHighway   = "highway"
+	TagLinear    = "Linear"
+	TagTravel    = "travel"
+	TagFill      = "Fill"
+	TagSemiFill  = "semi-fill"
+	TagChaotic   = "Chaotic"
+	TagFractal   = "Fractal"
+	TagSymmetric = "Symmetric"
+	TagStable    = "stable"
+)
+
+// Features are the measures of a run used to classify a rule
+type Features struct {
+	Steps      string
+	TotalSteps int64
+	// Stuck is true if the ant reached the border of the board before the budget
+	Stuck bool
+	// HighwayPeriod is the number of steps after which the ant repeats its moves shifted by HighwayShift, 0 without highway
+	HighwayPeriod int
+	HighwayShift  Point
+	// FillRatio is the fraction of the bounding box of the visited cells that has been visited
+	FillRatio float64
+	// Symmetry is the best symmetry found in the second half of the run
+	Symmetry SymmetryReport
+	// GrowthExponent and FractalDimension are the metrics of Growth
+	GrowthExponent   float64
+	FractalDimension float64
+	// Entropy is the mean entropy around the ant in the second half of the run divided by the maximum entropy of the rule
+	Entropy float64
+	// EntropyVariation is the coefficient of variation of the entropy around the ant in the second half of the run
+	EntropyVariation float64
+	// Still is true if the cells around the ant did not change in the last samples
+	Still bool
+}
+
+// Classifier runs rules for a budget of steps and tags them from the measured features
+type Classifier struct {
+	// Budget is the number of steps of each run, DefaultClassifierBudget if 0
+	Budget int64
+	// Size is the distance from the center to the sides of the board, DefaultClassifierSize if 0
+	Size int64
+	// MaxPeriod is the longest highway period searched, DefaultMaxHighwayPeriod if 0
+	MaxPeriod int
+	// Samples is the number of samples taken during the run, DefaultClassifierSamples if 0
+	Samples int
+	// Radius is the radius of the window around the ant used for the entropy, DefaultClassifierRadius if 0
+	Radius int64
+}
+
+// DefaultClassifierRadius is the radius of the entropy window of a Classifier with Radius 0
+const DefaultClassifierRadius = 10
+
+func (classifier *Classifier) budget() int64 {
+	if classifier.Budget <= 0 {
+		return DefaultClassifierBudget
+	}
+	return classifier.Budget
+}
+
+func (classifier *Classifier) size() int64 {
+	if classifier.Size <= 0 {
+		return DefaultClassifierSize
+	}
+	return classifier.Size
+}
+
+func (classifier *Classifier) maxPeriod() int {
+	if classifier.MaxPeriod <= 0 {
+		return DefaultMaxHighwayPeriod
+	}
+	return classifier.MaxPeriod
+}
+
+func (classifier *Classifier) samples() int {
+	if classifier.Samples <= 0 {
+		return DefaultClassifierSamples
+	}
+	return classifier.Samples
+}
+
+func (classifier *Classifier) radius() int64 {
+	if classifier.Radius <= 0 {
+		return DefaultClassifierRadius
+	}
+	return classifier.Radius
+}
+
+// Classify runs the rule and returns its tags and the features they come from
+func (classifier *Classifier) Classify(steps Steps) ([]string, Features, error) {
+	features, err := classifier.Features(steps)
+	if err != nil {
+		return nil, features, err
+	}
+	return features.Tags(), features, nil
+}
+
+// Features runs the rule in a CompactBoard until the budget is spent or the ant reaches the border, and measures it
+func (classifier *Classifier) Features(steps Steps) (Features, error) {
+	if len(steps) > MaxCompactSteps {
+		return Features{}, ErrInvalidSteps
+	}
+	dimensions := NewBoard(classifier.size())
+	ant := NewAntOnBoard(NewCompactBoard(dimensions, steps), numerated(steps)...)
+	return classifier.Measure(ant)
+}
+
+// Measure moves the ant the budget of steps, or until it gets stuck, and returns the features of the run
+func (classifier *Classifier) Measure(ant *Ant) (Features, error) {
+	budget := classifier.budget()
+	samples := classifier.samples()
+	radius := classifier.radius()
+	trail := newTrail(trailSize(classifier.maxPeriod()))
+	growth := NewGrowth(ant)
+	sampleSteps := GrowthSteps(budget, samples)
+	// the symmetry is expensive to measure, it is only checked a few times at the end of the run
+	symmetryChecks := 4
+	entropies := []float64{}
+	windows := []uint64{}
+	best := SymmetryReport{}
+
+	features := Features{
+		Steps: Steps(ant.steps).String(),
+	}
+	start := ant.totalSteps
+	for i, step := range sampleSteps {
+		for ant.totalSteps < start+step {
+			_, err := ant.Next()
+			if errors.Is(err, ErrOutOfBounds) {
+				features.Stuck = true
+				break
+			}
+			if err != nil {
+				return features, err
+			}
+			trail.add(ant.Position.Point, ant.lastAction)
+		}
+		growth.Sample(ant)
+		if i >= len(sampleSteps)/2 {
+			entropies = append(entropies, ant.EntropyAround(radius))
+			windows = append(windows, ant.windowHash(radius))
+		}
+		if i >= len(sampleSteps)-symmetryChecks || features.Stuck {
+			if report := ant.Symmetries()[0]; report.Score > best.Score {
+				best = report
+			}
+		}
+		if features.Stuck {
+			break
+		}
+	}
+	growth.Fit(ant)
+
+	features.TotalSteps = ant.totalSteps - start
+	features.HighwayPeriod, features.HighwayShift = trail.highway(classifier.maxPeriod())
+	features.FillRatio = fillRatio(ant)
+	features.Symmetry = best
+	features.GrowthExponent = growth.Exponent
+	features.FractalDimension = growth.FractalDimension
+	mean, variation := meanVariation(entropies)
+	if maxEntropy := math.Log2(float64(len(ant.steps))); maxEntropy > 0 {
+		features.Entropy = mean / maxEntropy
+	}
+	features.EntropyVariation = variation
+	features.Still = len(windows) >= 3 && windows[len(windows)-1] == windows[len(windows)-2] && windows[len(windows)-2] == windows[len(windows)-3]
+	return features, nil
+}
+
+// Thresholds on the features used by Tags
+const (
+	fillThreshold     = 0.85
+	semiFillThreshold = 0.5
+	linearThreshold   = 0.3
+	symmetryThreshold = 0.98
+	chaoticEntropy    = 0.75
+	fractalMinimum    = 1.2
+	fractalMaximum    = 1.8
+)
+
+// Tags returns the labels of the features in the vocabulary of patterns.txt.
+// Only highway, Linear, travel, Fill, semi-fill, Chaotic, Fractal, Symmetric and stable are produced,
+// the other labels, like uniform or Triangles, describe how the pattern looks and have no reliable measure.
+// A highway that leaves a thin trail is Linear and a highway that drags a filled area is travel
+func (features Features) Tags() []string {
+	tags := []string{}
+	highway := features.HighwayPeriod > 0
+	if !highway && features.Entropy >= chaoticEntropy && features.Symmetry.Score < symmetryThreshold && !features.Still {
+		tags = append(tags, TagChaotic)
+	}
+	fill := features.FillRatio >= semiFillThreshold
+	switch {
+	case highway && features.FillRatio >= fillThreshold:
+		tags = append(tags, TagTravel)
+	case highway && features.FillRatio < linearThreshold:
+		tags = append(tags, TagHighway, TagLinear)
+	case highway:
+		tags = append(tags, TagHighway)
+	}
+	switch {
+	case features.FillRatio >= fillThreshold:
+		tags = append(tags, TagFill)
+	case fill:
+		tags = append(tags, TagSemiFill)
+	}
+	if features.FractalDimension >= fractalMinimum && features.FractalDimension <= fractalMaximum && !fill {
+		tags = append(tags, TagFractal)
+	}
+	if features.Symmetry.Score >= symmetryThreshold {
+		tags = append(tags, TagSymmetric)
+	}
+	if features.Still {
+		tags = append(tags, TagStable)
+	}
+	return tags
+}
+
+// fillRatio returns the fraction of the bounding box of the visited cells that has been visited
+func fillRatio(ant *Ant) float64 {
+	var visited int64
+	for _, count := range ant.histogram {
+		visited += count
+	}
+	if ant.visited.Size == 0 {
+		return 0
+	}
+	return float64(visited) / float64(ant.visited.Size)
+}
+
+// meanVariation returns the mean of the values and their standard deviation divided by the mean
+func meanVariation(values []float64) (mean float64, variation float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+	if mean == 0 {
+		return 0, 0
+	}
+	deviation := 0.0
+	for _, value := range values {
+		deviation += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(deviation/float64(len(values))) / mean
+}
+
+// windowHash returns a hash of the cells in the square around the ant
+func (ant *Ant) windowHash(radius int64) uint64 {
+	p := ant.Position.Point
+	var hash uint64
+	ant.Region(NewDimensions(p.X-radius, p.Y-radius, p.X+radius, p.Y+radius), func(cell Cell) bool {
+		hash ^= hashCell(Cell{
+			Point: Point{X: cell.X - p.X, Y: cell.Y - p.Y},
+			Step:  cell.Step,
+		})
+		return true
+	})
+	return hash
+}
+
+// trail keeps the last positions and actions of an ant in a ring buffer
+type trail struct {
+	positions []Point
+	actions   []Action
+	next      int
+	length    int
+}
+
+func newTrail(size int) *trail {
+	return &trail{
+		positions: make([]Point, size),
+		actions:   make([]Action, size),
+	}
+}
+
+func (trail *trail) add(p Point, action Action) {
+	trail.positions[trail.next] = p
+	trail.actions[trail.next] = action
+	trail.next = (trail.next + 1) % len(trail.positions)
+	if trail.length < len(trail.positions) {
+		trail.length++
+	}
+}
+
+// at returns the position and action i steps ago, 0 is the last one
+func (trail *trail) at(i int) (Point, Action) {
+	index := (trail.next - 1 - i + 2*len(trail.positions)) % len(trail.positions)
+	return trail.positions[index], trail.actions[index]
+}
+
+// A highway must repeat its moves at least highwayRepeats times and for at least highwayMinSteps,
+// otherwise short zigzags of chaotic rules would be taken as highways
+const (
+	highwayRepeats  = 3
+	highwayMinSteps = 1000
+)
+
+// trailSize returns the number of moves needed to find highways up to maxPeriod
+func trailSize(maxPeriod int) int {
+	return highwayWindow(maxPeriod) + maxPeriod
+}
+
+// highwayWindow returns the number of last moves that must repeat for a highway with the given period
+func highwayWindow(period int) int {
+	if highwayRepeats*period < highwayMinSteps {
+		return highwayMinSteps
+	}
+	return highwayRepeats * period
+}
+
+// highway returns the shortest period up to maxPeriod such that the last moves are repeated at least highwayRepeats
+// times, each time shifted by the same displacement. The period is 0 if there is none
+func (trail *trail) highway(maxPeriod int) (int, Point) {
+	for period := 1; period <= maxPeriod && highwayWindow(period)+period <= trail.length; period++ {
+		last, _ := trail.at(0)
+		previous, _ := trail.at(period)
+		shift := Point{X: last.X - previous.X, Y: last.Y - previous.Y}
+		if shift == (Point{}) {
+			continue
+		}
+		repeated := true
+		for i := 0; i < highwayWindow(period) && repeated; i++ {
+			p, action := trail.at(i)
+			q, other := trail.at(i + period)
+			repeated = action == other && p.X-q.X == shift.X && p.Y-q.Y == shift.Y
+		}
+		if repeated {
+			return period, shift
+		}
+	}
+	return 0, Point{}
+}
@@ -0,0 +1,113 @@
+package langton
+
+import (
+	"testing"
+)
+
+func TestClassifierHighway(t *testing.T) {
+	classifier := &Classifier{Budget: 20000, Size: 100}
+	tags, features, err := classifier.Classify(StepsFromString("LR"))
+	if err != nil {
+		t.Fatalf("Classify() error = %v", err)
+	}
+	if features.HighwayPeriod != 104 {
+		t.Errorf("Classify() HighwayPeriod = %v, want 104", features.HighwayPeriod)
+	}
+	if features.HighwayShift.X*features.HighwayShift.X != 4 || features.HighwayShift.Y*features.HighwayShift.Y != 4 {
+		t.Errorf("Classify() HighwayShift = %v, want a diagonal of 2 cells", features.HighwayShift)
+	}
+	if !hasTag(tags, TagHighway) || hasTag(tags, TagChaotic) {
+		t.Errorf("Classify() = %v, want a highway", tags)
+	}
+}
+
+func TestClassifierChaotic(t *testing.T) {
+	classifier := &Classifier{Budget: 20000, Size: 100}
+	tags, features, err := classifier.Classify(StepsFromString("RLR"))
+	if err != nil {
+		t.Fatalf("Classify() error = %v", err)
+	}
+	if features.HighwayPeriod != 0 {
+		t.Errorf("Classify() HighwayPeriod = %v, want 0", features.HighwayPeriod)
+	}
+	if !hasTag(tags, TagChaotic) {
+		t.Errorf("Classify() = %v, want Chaotic", tags)
+	}
+}
+
+func TestTrailHighway(t *testing.T) {
+	trail := newTrail(trailSize(10))
+	// a cycle of period 3 that moves one cell right
+	actions := []Action{ActionTurnLeft, ActionTurnRight, ActionTurnRight}
+	for i := int64(0); i < int64(trailSize(10)+10); i++ {
+		trail.add(Point{X: i / 3, Y: i % 3}, actions[i%3])
+	}
+	period, shift := trail.highway(10)
+	if period != 3 || shift != (Point{X: 1}) {
+		t.Errorf("highway() = %v, %v, want 3, (1, 0)", period, shift)
+	}
+
+	trail = newTrail(trailSize(10))
+	for i := int64(0); i < highwayMinSteps/2; i++ {
+		trail.add(Point{X: i / 3, Y: i % 3}, actions[i%3])
+	}
+	if period, _ := trail.highway(10); period != 0 {
+		t.Errorf("highway() = %v, want 0 for a short trail", period)
+	}
+}
+
+func TestFeaturesTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		features Features
+		want     []string
+	}{
+		{
+			name:     "linear highway",
+			features: Features{HighwayPeriod: 104, FillRatio: 0.1, FractalDimension: 1.5},
+			want:     []string{TagHighway, TagLinear, TagFractal},
+		},
+		{
+			name:     "travel",
+			features: Features{HighwayPeriod: 20, FillRatio: 0.9, Entropy: 0.9},
+			want:     []string{TagTravel, TagFill},
+		},
+		{
+			name:     "chaotic fill",
+			features: Features{FillRatio: 0.9, Entropy: 0.9, FractalDimension: 1.9},
+			want:     []string{TagChaotic, TagFill},
+		},
+		{
+			name:     "symmetric",
+			features: Features{FillRatio: 0.6, Entropy: 0.9, Symmetry: SymmetryReport{Score: 1}},
+			want:     []string{TagSemiFill, TagSymmetric},
+		},
+		{
+			name:     "stable",
+			features: Features{FillRatio: 0.2, Entropy: 0.9, Still: true},
+			want:     []string{TagStable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.features.Tags()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Tags() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Tags() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package langton
+
+import (
+	"sync"
+)
+
+// DefaultRegionSize is the side of the square regions used by Colony to step ants in parallel
+const DefaultRegionSize = 64
+
+// Colony is a group of ants that walk the same board following the same steps.
+//
+// The order is always the sequential one: on every tick each ant that is not stuck performs one step,
+// in the order they were added, and sees the cells already changed by the ants before it in the same tick.
+//
+// With more than one worker the board is split in square regions and ants in regions that can not interact
+// are stepped at the same time. An ant only reads and writes its cell and the 4 adjacent ones, so two ants
+// more than 2 cells apart never touch the same cell and their steps can run in any order.
+// Regions with ants close to each other across the border are joined and stepped together in the sequential order,
+// so the result is exactly the same as NextSequential.
+// Boards that are not safe for concurrent writes to different cells, and ants with noise, schedules or sensing rules,
+// are always stepped sequentially.
+//
+// Colony ants do not track the histogram and the hash because the board is shared
+type Colony struct {
+	Board Board
+	// Workers is the number of goroutines used by Next, 0 or 1 steps every ant in the calling goroutine
+	Workers int
+	// RegionSize is the side of the regions in cells, DefaultRegionSize if 0
+	RegionSize int64
+
+	steps Steps
+	ants  []*Ant
+	ticks int64
+}
+
+// NewColony creates a colony without ants on the given board
+func NewColony(board Board, steps ...Step) *Colony {
+	return &Colony{
+		Board: board,
+		steps: numerated(steps),
+	}
+}
+
+// Add places a new ant at the given position facing the direction.
+// It is seeded with its index so ants with stochastic steps do not take the same decisions
+func (colony *Colony) Add(position Point, direction Direction) (*Ant, error) {
+	ant := &Ant{
+		Board:     colony.Board,
+		Direction: direction,
+		steps:     colony.steps,
+		random:    NewRandom(int64(len(colony.ants))),
+		untracked: true,
+	}
+	cell, _, err := ant.ensureCellAt(position)
+	if err != nil {
+		return nil, err
+	}
+	ant.Position = cell
+	ant.visited = NewDimensions(cell.X, cell.Y, cell.X, cell.Y)
+	ant.setCell(cell)
+	colony.ants = append(colony.ants, ant)
+	return ant, nil
+}
+
+// Ants returns the ants of the colony in the order they are stepped
+func (colony *Colony) Ants() []*Ant {
+	return colony.ants
+}
+
+// Ticks returns the number of ticks performed by the colony
+func (colony *Colony) Ticks() int64 {
+	return colony.ticks
+}
+
+// Steps returns the sequence of steps followed by the ants
+func (colony *Colony) Steps() Steps {
+	return colony.steps
+}
+
+// Next performs a tick, in parallel if there are several workers.
+// The result is always the same as NextSequential
+func (colony *Colony) Next() {
+	if colony.Workers <= 1 || !colony.parallel() {
+		colony.NextSequential()
+		return
+	}
+	groups := colony.groups()
+	work := make(chan []int)
+	wg := sync.WaitGroup{}
+	wg.Add(colony.Workers)
+	for i := 0; i < colony.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for group := range work {
+				for _, i := range group {
+					colony.step(colony.ants[i])
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		work <- group
+	}
+	close(work)
+	wg.Wait()
+	colony.ticks++
+}
+
+// NextSequential performs a tick stepping every ant in order, it is the reference for Next
+func (colony *Colony) NextSequential() {
+	for _, ant := range colony.ants {
+		colony.step(ant)
+	}
+	colony.ticks++
+}
+
+// NextN performs n ticks
+func (colony *Colony) NextN(ticks int) {
+	if ticks < 0 {
+		panic("ticks must be >= 0")
+	}
+	for i := 0; i < ticks; i++ {
+		colony.Next()
+	}
+}
+
+// step moves the ant once unless it is stuck.
+// The cell under the ant may have been changed by other ants, so it is read again first
+func (colony *Colony) step(ant *Ant) {
+	if ant.stuck {
+		return
+	}
+	ant.Position, _ = ant.Board.Cell(ant.Position.Point)
+	ant.Next()
+}
+
+// parallel returns true if the board and the ants allow parallel steps
+func (colony *Colony) parallel() bool {
+	switch colony.Board.(type) {
+	case *DenseBoard, *CompactBoard:
+	default:
+		return false
+	}
+	for _, ant := range colony.ants {
+		if ant.noise != nil || ant.schedule != nil || ant.sensing != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// colonyReach is the distance at which two ants may touch the same cell in a step
+const colonyReach = 2
+
+// groups returns the indexes of the ants that must be stepped together, sorted in the sequential order.
+// Ants of different groups can be stepped at the same time
+func (colony *Colony) groups() [][]int {
+	size := colony.RegionSize
+	if size <= 0 {
+		size = DefaultRegionSize
+	}
+	regionOf := func(p Point) Point {
+		return Point{
+			X: floorDiv(p.X, size),
+			Y: floorDiv(p.Y, size),
+		}
+	}
+
+	// regions are joined with a union find keyed by the region coordinates
+	parent := map[Point]Point{}
+	var find func(r Point) Point
+	find = func(r Point) Point {
+		p, ok := parent[r]
+		if !ok || p == r {
+			return r
+		}
+		root := find(p)
+		parent[r] = root
+		return root
+	}
+	union := func(a, b Point) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		// the smallest region is the root, so the result does not depend on the map order
+		if rb.Y < ra.Y || (rb.Y == ra.Y && rb.X < ra.X) {
+			ra, rb = rb, ra
+		}
+		parent[rb] = ra
+	}
+
+	positions := map[Point][]int{}
+	for i, ant := range colony.ants {
+		if ant.stuck {
+			continue
+		}
+		positions[ant.Position.Point] = append(positions[ant.Position.Point], i)
+	}
+	for i, ant := range colony.ants {
+		if ant.stuck {
+			continue
+		}
+		p := ant.Position.Point
+		region := regionOf(p)
+		inside := p.X-region.X*size >= colonyReach && (region.X+1)*size-1-p.X >= colonyReach &&
+			p.Y-region.Y*size >= colonyReach && (region.Y+1)*size-1-p.Y >= colonyReach
+		if inside {
+			continue
+		}
+		for dx := int64(-colonyReach); dx <= colonyReach; dx++ {
+			for dy := int64(-colonyReach); dy <= colonyReach; dy++ {
+				if abs64(dx)+abs64(dy) > colonyReach {
+					continue
+				}
+				q := Point{X: p.X + dx, Y: p.Y + dy}
+				for _, j := range positions[q] {
+					if j != i && regionOf(q) != region {
+						union(region, regionOf(q))
+					}
+				}
+			}
+		}
+	}
+
+	groups := [][]int{}
+	index := map[Point]int{}
+	for i, ant := range colony.ants {
+		if ant.stuck {
+			continue
+		}
+		root := find(regionOf(ant.Position.Point))
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+func abs64(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
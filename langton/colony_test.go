@@ -0,0 +1,74 @@
+package langton
+
+import "testing"
+
+func newTestColony(board Board, workers int) *Colony {
+	colony := NewColony(board, StepsFromString("RL[L0.7R]LLRRS")...)
+	colony.Workers = workers
+	colony.RegionSize = 8
+	random := NewRandom(42)
+	dimensions := board.Dimensions()
+	for i := 0; i < 200; i++ {
+		p := Point{
+			X: dimensions.BottomLeft.X + int64(random.Intn(int(dimensions.Width()))),
+			Y: dimensions.BottomLeft.Y + int64(random.Intn(int(dimensions.Height()))),
+		}
+		if _, err := colony.Add(p, Direction(random.Intn(int(DirectionInvalid)))); err != nil {
+			panic(err)
+		}
+	}
+	return colony
+}
+
+func TestColony_ParallelMatchesSequential(t *testing.T) {
+	dimensions := NewBoard(60)
+	boards := map[string]func() Board{
+		"dense": func() Board { return NewDenseBoard(dimensions) },
+		"compact": func() Board {
+			return NewCompactBoard(dimensions, StepsFromString("RL[L0.7R]LLRRS"))
+		},
+	}
+	for name, board := range boards {
+		t.Run(name, func(t *testing.T) {
+			sequential := newTestColony(board(), 1)
+			parallel := newTestColony(board(), 4)
+			for tick := 0; tick < 300; tick++ {
+				sequential.NextSequential()
+				parallel.Next()
+			}
+			for i, ant := range parallel.Ants() {
+				want := sequential.Ants()[i]
+				if ant.Position != want.Position || ant.Direction != want.Direction || ant.Stuck() != want.Stuck() {
+					t.Fatalf("ant %d = %v %v, want %v %v", i, ant.Position, ant.Direction, want.Position, want.Direction)
+				}
+			}
+			diffs := parallel.Ants()[0].Diff(sequential.Ants()[0])
+			if len(diffs) != 0 {
+				t.Errorf("parallel board differs in %d cells, first %v", len(diffs), diffs[0])
+			}
+			if parallel.Ticks() != 300 {
+				t.Errorf("Colony.Ticks() = %d, want %d", parallel.Ticks(), 300)
+			}
+		})
+	}
+}
+
+func TestColony_Groups(t *testing.T) {
+	colony := NewColony(NewDenseBoard(NewBoard(20)), StepsFromString("LR")...)
+	colony.RegionSize = 8
+	for _, p := range []Point{{X: 1, Y: 1}, {X: 7, Y: 1}, {X: 8, Y: 2}, {X: -5, Y: -5}, {X: 3, Y: 4}, {X: 12, Y: 12}} {
+		colony.Add(p, DirectionTop)
+	}
+	got := colony.groups()
+	want := [][]int{{0, 1, 2, 4}, {3}, {5}}
+	if len(got) != len(want) {
+		t.Fatalf("Colony.groups() = %v, want %v", got, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Fatalf("Colony.groups() = %v, want %v", got, want)
+			}
+		}
+	}
+}
@@ -28,9 +28,9 @@ func NewDimensions(minX, minY, maxX, maxY int64) Dimensions {
 
 // Init must be always called after creation, it precalculates some internal values
 func (dim *Dimensions) Init() {
-	dim.height = dim.TopRight.X - dim.BottomLeft.X + 1
-	dim.width = dim.TopRight.Y - dim.BottomLeft.Y + 1
-	dim.Size = dim.height * dim.width
+	dim.width = dim.TopRight.X - dim.BottomLeft.X + 1
+	dim.height = dim.TopRight.Y - dim.BottomLeft.Y + 1
+	dim.Size = dim.width * dim.height
 }
 
 // Dimensions represent the area that the ant can explore
@@ -82,6 +82,8 @@ func (d Direction) Turn(action Action) Direction {
 		return (d + DirectionInvalid + 1) % DirectionInvalid
 	case ActionStraight:
 		return d
+	case ActionUTurn:
+		return (d + 2) % DirectionInvalid
 	default:
 		panic("Invalid action provided")
 	}
@@ -96,6 +98,8 @@ func (d Direction) Unturn(action Action) Direction {
 		return (d + DirectionInvalid - 1) % DirectionInvalid
 	case ActionStraight:
 		return d
+	case ActionUTurn:
+		return (d + 2) % DirectionInvalid
 	default:
 		panic("Invalid action provided")
 	}
@@ -125,18 +129,18 @@ func (dim *Dimensions) Center() Point {
 	}
 }
 
-// Width returns the width of the Dimensions
+// Width returns the width of the Dimensions, the number of columns along X
 func (dim *Dimensions) Width() int64 {
 	return dim.width
 }
 
-// Height returns the Height of the Dimensions
+// Height returns the height of the Dimensions, the number of rows along Y
 func (dim *Dimensions) Height() int64 {
 	return dim.height
 }
 
-// isPointInside returns true if the point is inside the Dimensions
-func (dim *Dimensions) isPointInside(p Point) bool {
+// Contains returns true if the point is inside the Dimensions
+func (dim *Dimensions) Contains(p Point) bool {
 	return p.X >= dim.BottomLeft.X &&
 		p.X <= dim.TopRight.X &&
 		p.Y >= dim.BottomLeft.Y &&
@@ -147,7 +151,69 @@ func (dim *Dimensions) isPointInside(p Point) bool {
 func (dim *Dimensions) indexOf(p Point) int {
 	x := p.X - dim.BottomLeft.X
 	y := p.Y - dim.BottomLeft.Y
-	return int((x) + (y)*dim.height)
+	return int((x) + (y)*dim.width)
+}
+
+// pointOf returns the point of a given index, the opposite of indexOf
+func (dim *Dimensions) pointOf(index int) Point {
+	return Point{
+		X: dim.BottomLeft.X + int64(index)%dim.width,
+		Y: dim.BottomLeft.Y + int64(index)/dim.width,
+	}
+}
+
+// ContainsDimensions returns true if other is completely inside the Dimensions
+func (dim *Dimensions) ContainsDimensions(other Dimensions) bool {
+	return dim.Contains(other.BottomLeft) && dim.Contains(other.TopRight)
+}
+
+// Expand returns the Dimensions with a margin added in every direction
+func (dim Dimensions) Expand(margin int64) Dimensions {
+	return NewDimensions(
+		dim.BottomLeft.X-margin,
+		dim.BottomLeft.Y-margin,
+		dim.TopRight.X+margin,
+		dim.TopRight.Y+margin,
+	)
+}
+
+// Intersect returns the area shared by both Dimensions, ok is false if they do not overlap
+func (dim Dimensions) Intersect(other Dimensions) (Dimensions, bool) {
+	minX := max64(dim.BottomLeft.X, other.BottomLeft.X)
+	minY := max64(dim.BottomLeft.Y, other.BottomLeft.Y)
+	maxX := min64(dim.TopRight.X, other.TopRight.X)
+	maxY := min64(dim.TopRight.Y, other.TopRight.Y)
+	if minX > maxX || minY > maxY {
+		return Dimensions{}, false
+	}
+	return NewDimensions(minX, minY, maxX, maxY), true
+}
+
+// extend returns the Dimensions grown to include the point
+func (dim Dimensions) extend(p Point) Dimensions {
+	if dim.Contains(p) {
+		return dim
+	}
+	return NewDimensions(
+		min64(dim.BottomLeft.X, p.X),
+		min64(dim.BottomLeft.Y, p.Y),
+		max64(dim.TopRight.X, p.X),
+		max64(dim.TopRight.Y, p.Y),
+	)
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
 }
 
 // String returns a string representation
@@ -0,0 +1,140 @@
+package langton
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+var ErrInvalidInterval = errors.New("Invalid interval")
+
+// Entropy returns the Shannon entropy in bits of the frequencies given by counts.
+// It is 0 when a single index is used and log2(len(counts)) when every index is equally frequent
+func Entropy(counts []int64) float64 {
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		if count == 0 {
+			continue
+		}
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Entropy returns the entropy of the step indexes of the visited cells, it is cheap as the histogram is kept up to date
+func (ant *Ant) Entropy() float64 {
+	return Entropy(ant.histogram)
+}
+
+// EntropyAround returns the entropy of the step indexes of the visited cells in the square around the ant
+// with the given radius. Cells that have not been visited are ignored
+func (ant *Ant) EntropyAround(radius int64) float64 {
+	p := ant.Position.Point
+	return Entropy(ant.HistogramIn(NewDimensions(p.X-radius, p.Y-radius, p.X+radius, p.Y+radius)))
+}
+
+// EntropySample is the entropy of the board at a step
+type EntropySample struct {
+	Step int64 `json:"step"`
+	// Visited is the entropy of all the visited cells
+	Visited float64 `json:"visited"`
+	// Window is the entropy of the visited cells around the ant
+	Window float64 `json:"window"`
+}
+
+// EntropySeries is a time series of the entropy of the step indexes over the visited area and around the ant.
+// The window follows the ant, so it shows changes of behaviour long before they are visible in the whole board
+type EntropySeries struct {
+	Steps string `json:"steps"`
+	// Colours is the number of steps of the rule, the entropy is at most log2(Colours)
+	Colours int `json:"colours"`
+	// Interval is the number of steps between samples
+	Interval int64 `json:"interval"`
+	// Radius is the distance from the ant to the sides of the window
+	Radius  int64           `json:"radius"`
+	Samples []EntropySample `json:"samples"`
+}
+
+// NewEntropySeries creates an empty series for the ant that samples every interval steps a window with the given radius
+func NewEntropySeries(ant *Ant, interval int64, radius int64) *EntropySeries {
+	return &EntropySeries{
+		Steps:    Steps(ant.steps).String(),
+		Colours:  len(ant.steps),
+		Interval: interval,
+		Radius:   radius,
+	}
+}
+
+// MaxEntropy returns the highest possible entropy of the rule, it is used to compare rules with different lengths
+func (series *EntropySeries) MaxEntropy() float64 {
+	if series.Colours <= 1 {
+		return 0
+	}
+	return math.Log2(float64(series.Colours))
+}
+
+// Sample appends the current entropy of the ant to the series
+func (series *EntropySeries) Sample(ant *Ant) {
+	series.Samples = append(series.Samples, EntropySample{
+		Step:    ant.totalSteps,
+		Visited: ant.Entropy(),
+		Window:  ant.EntropyAround(series.Radius),
+	})
+}
+
+// Run moves the ant the given steps taking a sample before starting and then every Interval steps.
+// The samples taken until the ant gets stuck are kept and the error is returned.
+// It fails with ErrInvalidInterval if Interval is not positive
+func (series *EntropySeries) Run(ant *Ant, steps int64) error {
+	if series.Interval <= 0 {
+		return fmt.Errorf("%w: %d steps", ErrInvalidInterval, series.Interval)
+	}
+	series.Sample(ant)
+	for done := int64(0); done < steps; {
+		n := series.Interval
+		if steps-done < n {
+			n = steps - done
+		}
+		_, err := ant.NextN(int(n))
+		if err != nil {
+			return err
+		}
+		done += n
+		series.Sample(ant)
+	}
+	return nil
+}
+
+// WriteCSV writes a row per sample with the step and both entropies
+func (series *EntropySeries) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"step", "visited", "window"})
+	for _, sample := range series.Samples {
+		writer.Write([]string{
+			strconv.FormatInt(sample.Step, 10),
+			strconv.FormatFloat(sample.Visited, 'f', 6, 64),
+			strconv.FormatFloat(sample.Window, 'f', 6, 64),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the series with its sampling parameters
+func (series *EntropySeries) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(series)
+}
@@ -0,0 +1,99 @@
+package langton
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEntropy(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []int64
+		want   float64
+	}{
+		{name: "empty", counts: []int64{0, 0}, want: 0},
+		{name: "single", counts: []int64{0, 7, 0}, want: 0},
+		{name: "uniform", counts: []int64{3, 3, 3, 3}, want: 2},
+		{name: "skewed", counts: []int64{3, 1}, want: 0.8112781244591328},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Entropy(tt.counts); math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Entropy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntropySeries_Run(t *testing.T) {
+	ant := NewAntFromString(NewBoard(100), "RLLLLRRRLLL")
+	series := NewEntropySeries(ant, 100, 1000)
+	err := series.Run(ant, 1050)
+	if err != nil {
+		t.Fatalf("EntropySeries.Run() error = %v", err)
+	}
+	if len(series.Samples) != 12 {
+		t.Fatalf("EntropySeries.Run() took %d samples, want 12", len(series.Samples))
+	}
+	if last := series.Samples[11]; last.Step != 1050 {
+		t.Errorf("EntropySeries.Run() last sample at step %d, want 1050", last.Step)
+	}
+	for _, sample := range series.Samples {
+		if sample.Visited < 0 || sample.Visited > series.MaxEntropy() {
+			t.Errorf("sample %d entropy = %v, want in [0, %v]", sample.Step, sample.Visited, series.MaxEntropy())
+		}
+		// the window covers the whole board
+		if math.Abs(sample.Window-sample.Visited) > 1e-12 {
+			t.Errorf("sample %d window entropy = %v, want %v", sample.Step, sample.Window, sample.Visited)
+		}
+	}
+	if got, want := ant.Entropy(), Entropy(ant.Histogram()); got != want {
+		t.Errorf("Ant.Entropy() = %v, want %v", got, want)
+	}
+
+	buf := &bytes.Buffer{}
+	err = series.WriteCSV(buf)
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "step,visited,window" || len(lines) != len(series.Samples)+1 {
+		t.Errorf("WriteCSV() = %q", buf.String())
+	}
+}
+
+func TestEntropyAround(t *testing.T) {
+	steps := StepsFromString("LR")
+	steps.Numerate()
+	board := NewSparseBoard(NewBoard(50))
+	// the ant is in a square of a single colour and there is a square of the other colour far away
+	for y := int64(-2); y <= 2; y++ {
+		for x := int64(-2); x <= 2; x++ {
+			board.SetCell(Cell{Point: Point{X: x, Y: y}, Step: steps[1]})
+			board.SetCell(Cell{Point: Point{X: x + 30, Y: y + 30}, Step: steps[0]})
+		}
+	}
+	ant := NewAntOnBoard(board, steps...)
+
+	series := NewEntropySeries(ant, 1, 2)
+	series.Sample(ant)
+	sample := series.Samples[0]
+	if sample.Window != 0 {
+		t.Errorf("sample window entropy = %v, want 0", sample.Window)
+	}
+	if math.Abs(sample.Visited-1) > 1e-12 {
+		t.Errorf("sample visited entropy = %v, want 1", sample.Visited)
+	}
+	// the window only sees the colour of the far square once it is inside
+	if got := ant.EntropyAround(40); math.Abs(got-1) > 1e-12 {
+		t.Errorf("EntropyAround(40) = %v, want 1", got)
+	}
+
+	err := NewEntropySeries(ant, 0, 2).Run(ant, 10)
+	if !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("EntropySeries.Run() error = %v, want %v", err, ErrInvalidInterval)
+	}
+}
@@ -0,0 +1,468 @@
+package langton
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Golly turmite turns, the ant is relative to its direction
+const (
+	gollyStraight = 1
+	gollyRight    = 2
+	gollyUTurn    = 4
+	gollyLeft     = 8
+)
+
+// gollyRulePrefix is the prefix of the turmite rules generated by Golly
+const gollyRulePrefix = "Turmite_"
+
+// gollyMaxColours is the number of colours that can be written with a single digit in a Golly rule name
+const gollyMaxColours = 10
+
+var ErrInvalidGolly = errors.New("Invalid Golly pattern")
+
+// GollyRule returns the name of the Golly turmite rule equivalent to the steps, "LR" is "Turmite_180020".
+// Golly names use a single digit per colour, so rules with more than 10 steps can not be written
+func GollyRule(steps Steps) (string, error) {
+	if len(steps) > gollyMaxColours {
+		return "", fmt.Errorf("%w: Golly rule names support up to %d colours", ErrInvalidGolly, gollyMaxColours)
+	}
+	builder := strings.Builder{}
+	builder.WriteString(gollyRulePrefix)
+	for i, step := range steps {
+		if step.Stochastic() {
+			return "", fmt.Errorf("%w: stochastic steps are not supported by Golly", ErrInvalidGolly)
+		}
+		turn, err := gollyTurn(step.Action)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(strconv.Itoa((i + 1) % len(steps)))
+		builder.WriteString(strconv.Itoa(turn))
+		builder.WriteString("0")
+	}
+	return builder.String(), nil
+}
+
+// GollyRule returns the name of the Golly turmite rule, the triples of every state one after the other.
+// Golly names use a single digit per value, so turmites with more than 10 colours or states can not be written
+func (turmite *Turmite) GollyRule() (string, error) {
+	err := turmite.Validate()
+	if err != nil {
+		return "", err
+	}
+	if turmite.Colours() > gollyMaxColours || turmite.States() > gollyMaxColours {
+		return "", fmt.Errorf("%w: Golly rule names support up to %d colours and states", ErrInvalidGolly, gollyMaxColours)
+	}
+	builder := strings.Builder{}
+	builder.WriteString(gollyRulePrefix)
+	for _, state := range turmite.Rules {
+		for _, rule := range state {
+			turn, _ := gollyTurn(rule.Turn)
+			builder.WriteString(strconv.Itoa(rule.Colour))
+			builder.WriteString(strconv.Itoa(turn))
+			builder.WriteString(strconv.Itoa(rule.State))
+		}
+	}
+	return builder.String(), nil
+}
+
+// ParseGollyTurmite returns the turmite of a Golly turmite rule name.
+// The name does not say how many states there are, the fewest states that fit the colours and states written are taken
+func ParseGollyTurmite(rule string) (*Turmite, error) {
+	if !strings.HasPrefix(rule, gollyRulePrefix) {
+		return nil, fmt.Errorf("%w: rule %q is not a turmite", ErrInvalidGolly, rule)
+	}
+	digits := strings.TrimPrefix(rule, gollyRulePrefix)
+	if len(digits) == 0 || len(digits)%3 != 0 {
+		return nil, fmt.Errorf("%w: rule %q must have 3 digits per colour and state", ErrInvalidGolly, rule)
+	}
+	rules := make([]TurmiteRule, len(digits)/3)
+	maxColour, maxState := 0, 0
+	for i := range rules {
+		colour, turn, state := int(digits[i*3]-'0'), int(digits[i*3+1]-'0'), int(digits[i*3+2]-'0')
+		if colour < 0 || colour > 9 || state < 0 || state > 9 {
+			return nil, fmt.Errorf("%w: rule %q must have only digits", ErrInvalidGolly, rule)
+		}
+		action, err := gollyAction(turn)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = TurmiteRule{
+			Colour: colour,
+			Turn:   action,
+			State:  state,
+		}
+		maxColour = maxInt(maxColour, colour)
+		maxState = maxInt(maxState, state)
+	}
+	for states := maxState + 1; states <= len(rules); states++ {
+		colours := len(rules) / states
+		if len(rules)%states != 0 || colours <= maxColour {
+			continue
+		}
+		turmite := &Turmite{}
+		for s := 0; s < states; s++ {
+			turmite.Rules = append(turmite.Rules, rules[s*colours:(s+1)*colours])
+		}
+		return turmite, nil
+	}
+	return nil, fmt.Errorf("%w: rule %q writes colours or states that do not exist", ErrInvalidGolly, rule)
+}
+
+// ParseGollyRule returns the steps of a Golly turmite rule name.
+// Only turmites with a single state that cycle through the colours like a langton ant are supported
+func ParseGollyRule(rule string) (Steps, error) {
+	if !strings.HasPrefix(rule, gollyRulePrefix) {
+		return nil, fmt.Errorf("%w: rule %q is not a turmite", ErrInvalidGolly, rule)
+	}
+	digits := strings.TrimPrefix(rule, gollyRulePrefix)
+	if len(digits) == 0 || len(digits)%3 != 0 {
+		return nil, fmt.Errorf("%w: rule %q must have 3 digits per colour", ErrInvalidGolly, rule)
+	}
+	colours := len(digits) / 3
+	steps := make(Steps, colours)
+	for i := range steps {
+		colour, turn, state := digits[i*3]-'0', digits[i*3+1]-'0', digits[i*3+2]-'0'
+		if state != 0 {
+			return nil, fmt.Errorf("%w: rule %q has more than one state", ErrInvalidGolly, rule)
+		}
+		if int(colour) != (i+1)%colours {
+			return nil, fmt.Errorf("%w: rule %q does not cycle through the colours", ErrInvalidGolly, rule)
+		}
+		action, err := gollyAction(int(turn))
+		if err != nil {
+			return nil, err
+		}
+		steps[i] = Step{
+			Action: action,
+		}
+	}
+	steps.Numerate()
+	return steps, nil
+}
+
+func gollyTurn(action Action) (int, error) {
+	switch action {
+	case ActionStraight:
+		return gollyStraight, nil
+	case ActionTurnRight:
+		return gollyRight, nil
+	case ActionTurnLeft:
+		return gollyLeft, nil
+	case ActionUTurn:
+		return gollyUTurn, nil
+	default:
+		return 0, fmt.Errorf("%w: action %q has no Golly turn", ErrInvalidGolly, action)
+	}
+}
+
+func gollyAction(turn int) (Action, error) {
+	switch turn {
+	case gollyStraight:
+		return ActionStraight, nil
+	case gollyRight:
+		return ActionTurnRight, nil
+	case gollyLeft:
+		return ActionTurnLeft, nil
+	case gollyUTurn:
+		return ActionUTurn, nil
+	default:
+		return ActionNone, fmt.Errorf("%w: unknown turn %d", ErrInvalidGolly, turn)
+	}
+}
+
+// gollyAntState returns the Golly cell state of an ant in the given turmite state on a cell with the given colour.
+// Golly encodes turmites as colours + 4*(states*colour+state) + direction, with the directions N, E, S, W as Direction
+func gollyAntState(colours, states, colour, state int, direction Direction) int {
+	return colours + 4*(states*colour+state) + int(direction)
+}
+
+// gollyMaxState is the highest cell state supported by Golly
+const gollyMaxState = 255
+
+// WriteRLE writes the visited cells and the ant in the Golly RLE format.
+// The pattern covers the bounding box of the visited cells with the top row first.
+// An ant that follows a turmite is written with every state of the turmite and the state it is in
+func WriteRLE(w io.Writer, ant *Ant) error {
+	var (
+		rule   string
+		err    error
+		states = 1
+		state  = 0
+	)
+	if ant.turmite != nil {
+		rule, err = ant.turmite.turmite.GollyRule()
+		states = ant.turmite.turmite.States()
+		state = ant.turmite.state
+	} else {
+		rule, err = GollyRule(ant.steps)
+	}
+	if err != nil {
+		return err
+	}
+	colours := len(ant.steps)
+	if gollyAntState(colours, states, colours-1, states-1, DirectionLeft) > gollyMaxState {
+		return fmt.Errorf("%w: too many colours and states", ErrInvalidGolly)
+	}
+	visited := ant.Visited()
+	writer := &rleWriter{w: bufio.NewWriter(w)}
+	fmt.Fprintf(writer.w, "x = %d, y = %d, rule = %s\n", visited.Width(), visited.Height(), rule)
+	for y := visited.TopRight.Y; y >= visited.BottomLeft.Y; y-- {
+		for x := visited.BottomLeft.X; x <= visited.TopRight.X; x++ {
+			p := Point{X: x, Y: y}
+			cell, ok := ant.Board.Cell(p)
+			colour := 0
+			if ok {
+				colour = cell.Step.Index
+			}
+			if p == ant.Position.Point {
+				colour = gollyAntState(colours, states, colour, state, ant.Direction)
+			}
+			writer.cell(colour)
+		}
+		writer.row()
+	}
+	return writer.end()
+}
+
+// rleWriter run length encodes the cells and wraps the lines at 70 characters
+type rleWriter struct {
+	w         *bufio.Writer
+	line      int
+	state     int
+	count     int
+	emptyRows int
+}
+
+func (writer *rleWriter) cell(state int) {
+	if writer.count > 0 && state != writer.state {
+		writer.flush()
+	}
+	writer.state = state
+	writer.count++
+}
+
+func (writer *rleWriter) row() {
+	// empty cells at the end of the row are implicit
+	if writer.state != 0 {
+		writer.flush()
+	}
+	writer.count = 0
+	writer.state = 0
+	writer.emptyRows++
+}
+
+func (writer *rleWriter) end() error {
+	writer.token(1, "!")
+	writer.w.WriteString("\n")
+	return writer.w.Flush()
+}
+
+func (writer *rleWriter) flush() {
+	if writer.emptyRows > 0 {
+		writer.token(writer.emptyRows, "$")
+		writer.emptyRows = 0
+	}
+	writer.token(writer.count, gollyStateString(writer.state))
+	writer.count = 0
+}
+
+func (writer *rleWriter) token(count int, tag string) {
+	token := tag
+	if count > 1 {
+		token = strconv.Itoa(count) + tag
+	}
+	if writer.line+len(token) > 70 {
+		writer.w.WriteString("\n")
+		writer.line = 0
+	}
+	writer.w.WriteString(token)
+	writer.line += len(token)
+}
+
+// gollyStateString returns the RLE representation of a cell state, "." for 0, "A" to "X" up to 24 and "pA" onwards
+func gollyStateString(state int) string {
+	if state == 0 {
+		return "."
+	}
+	state--
+	letter := string(rune('A' + state%24))
+	if state < 24 {
+		return letter
+	}
+	return string(rune('p'+state/24-1)) + letter
+}
+
+// ReadRLE reads a Golly RLE pattern with a single turmite and creates an Ant on a DenseBoard.
+// The ant is placed at the origin and the board has the given margin around the pattern.
+// Turmites with a single state that cycle through the colours are read as plain steps, any other follows the turmite
+func ReadRLE(r io.Reader, margin int64) (*Ant, error) {
+	scanner := bufio.NewScanner(r)
+	var (
+		rule   string
+		header bool
+		body   strings.Builder
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case !header:
+			header = true
+			for _, field := range strings.Split(line, ",") {
+				parts := strings.SplitN(field, "=", 2)
+				if len(parts) == 2 && strings.TrimSpace(parts[0]) == "rule" {
+					rule = strings.TrimSpace(parts[1])
+				}
+			}
+		default:
+			body.WriteString(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rule == "" {
+		return nil, fmt.Errorf("%w: missing rule", ErrInvalidGolly)
+	}
+	turmite, err := ParseGollyTurmite(rule)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := turmite.Steps()
+	if err != nil {
+		steps = turmite.Turns()
+	} else {
+		turmite = nil
+	}
+
+	cells, err := parseRLE(body.String())
+	if err != nil {
+		return nil, err
+	}
+	colours := len(steps)
+	states := 1
+	if turmite != nil {
+		states = turmite.States()
+	}
+	var (
+		ants      int
+		antCell   int
+		antState  int
+		direction Direction
+	)
+	maxRow, maxColumn := 0, 0
+	for i, cell := range cells {
+		if cell.state >= colours {
+			value := cell.state - colours
+			if value >= 4*colours*states {
+				return nil, fmt.Errorf("%w: invalid state %d", ErrInvalidGolly, cell.state)
+			}
+			ants++
+			antCell = i
+			direction = Direction(value % 4)
+			cells[i].state = value / 4 / states
+			antState = value / 4 % states
+		}
+		if cell.row > maxRow {
+			maxRow = cell.row
+		}
+		if cell.column > maxColumn {
+			maxColumn = cell.column
+		}
+	}
+	if ants != 1 {
+		return nil, fmt.Errorf("%w: found %d ants, want 1", ErrInvalidGolly, ants)
+	}
+
+	size := int64(maxRow)
+	if int64(maxColumn) > size {
+		size = int64(maxColumn)
+	}
+	board := NewDenseBoard(NewBoard(size + margin))
+	origin := cells[antCell]
+	for i, cell := range cells {
+		if cell.state == 0 && i != antCell {
+			continue
+		}
+		board.SetCell(Cell{
+			Point: Point{
+				X: int64(cell.column - origin.column),
+				Y: int64(origin.row - cell.row),
+			},
+			Step: steps[cell.state],
+		})
+	}
+	ant := NewAntOnBoard(board, steps...)
+	ant.Direction = direction
+	if turmite != nil {
+		err = ant.SetTurmite(turmite)
+		if err != nil {
+			return nil, err
+		}
+		ant.turmite.state = antState
+	}
+	return ant, nil
+}
+
+// rleCell is a cell of a RLE pattern, row 0 is the top one
+type rleCell struct {
+	row, column, state int
+}
+
+// parseRLE returns the cells of the body of a RLE pattern that are not empty, and the ant cell even if it is empty
+func parseRLE(body string) ([]rleCell, error) {
+	cells := []rleCell{}
+	row, column, count := 0, 0, 0
+	prefix := -1
+	for _, c := range body {
+		switch {
+		case c >= '0' && c <= '9':
+			count = count*10 + int(c-'0')
+			continue
+		case c == '!':
+			return cells, nil
+		case c == '$':
+			row += maxInt(count, 1)
+			column = 0
+		case c == 'b' || c == '.':
+			column += maxInt(count, 1)
+		case c == 'o':
+			cells, column = appendRun(cells, row, column, count, 1)
+		case c >= 'p' && c <= 'y':
+			prefix = int(c-'p'+1) * 24
+			continue
+		case c >= 'A' && c <= 'X':
+			state := int(c-'A') + 1
+			if prefix >= 0 {
+				state += prefix
+			}
+			cells, column = appendRun(cells, row, column, count, state)
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidGolly, c)
+		}
+		count = 0
+		prefix = -1
+	}
+	return nil, fmt.Errorf("%w: missing !", ErrInvalidGolly)
+}
+
+func appendRun(cells []rleCell, row, column, count, state int) ([]rleCell, int) {
+	for i := 0; i < maxInt(count, 1); i++ {
+		cells = append(cells, rleCell{row: row, column: column, state: state})
+		column++
+	}
+	return cells, column
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
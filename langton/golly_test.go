@@ -0,0 +1,159 @@
+package langton
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGollyRule(t *testing.T) {
+	tests := []struct {
+		steps string
+		rule  string
+	}{
+		{"RL", "Turmite_120080"},
+		{"LR", "Turmite_180020"},
+		{"RLLLLRRRLLL", ""},
+		{"LSR", "Turmite_180210020"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.steps, func(t *testing.T) {
+			got, err := GollyRule(StepsFromString(tt.steps))
+			if tt.rule == "" {
+				if err == nil {
+					t.Errorf("GollyRule() error = nil, want error")
+				}
+				return
+			}
+			if err != nil || got != tt.rule {
+				t.Fatalf("GollyRule() = %v, %v, want %v", got, err, tt.rule)
+			}
+			steps, err := ParseGollyRule(got)
+			if err != nil || steps.String() != tt.steps {
+				t.Errorf("ParseGollyRule() = %v, %v, want %v", steps, err, tt.steps)
+			}
+		})
+	}
+
+	for _, rule := range []string{"Turmite_120081", "Turmite_12008", "Turmite_130080", "Turmite_100080", "Langtons-Ant"} {
+		if _, err := ParseGollyRule(rule); err == nil {
+			t.Errorf("ParseGollyRule(%q) error = nil, want error", rule)
+		}
+	}
+}
+
+func TestRLE(t *testing.T) {
+	ant := NewAntFromString(NewBoard(60), "RLLR")
+	ant.NextN(5000)
+
+	buffer := &bytes.Buffer{}
+	if err := WriteRLE(buffer, ant); err != nil {
+		t.Fatalf("WriteRLE() error = %v", err)
+	}
+	for _, line := range strings.Split(buffer.String(), "\n") {
+		if len(line) > 70 {
+			t.Fatalf("WriteRLE() line has %d characters, want at most 70", len(line))
+		}
+	}
+
+	got, err := ReadRLE(bytes.NewReader(buffer.Bytes()), 20)
+	if err != nil {
+		t.Fatalf("ReadRLE() error = %v", err)
+	}
+	if got.Direction != ant.Direction {
+		t.Errorf("ReadRLE() direction = %v, want %v", got.Direction, ant.Direction)
+	}
+	offset := ant.Position.Point
+	ant.Board.Each(func(cell Cell) bool {
+		p := Point{X: cell.X - offset.X, Y: cell.Y - offset.Y}
+		other, ok := got.Board.Cell(p)
+		if cell.Step.Index != 0 && (!ok || other.Step.Index != cell.Step.Index) {
+			t.Fatalf("ReadRLE() cell %v = %v, want %v", p, other.Step, cell.Step)
+		}
+		return true
+	})
+
+	ant.NextN(1000)
+	got.NextN(1000)
+	if got.Position.X != ant.Position.X-offset.X || got.Position.Y != ant.Position.Y-offset.Y {
+		t.Errorf("ReadRLE() ant walks to %v, want %v", got.Position.Point, ant.Position.Point)
+	}
+}
+
+func TestReadRLE(t *testing.T) {
+	// a turmite facing north on an empty cell next to a cell with colour 1
+	pattern := `#C Langton's ant
+x = 2, y = 2, rule = Turmite_120080
+A$
+B!
+`
+	ant, err := ReadRLE(strings.NewReader(pattern), 2)
+	if err != nil {
+		t.Fatalf("ReadRLE() error = %v", err)
+	}
+	if ant.Direction != DirectionTop {
+		t.Errorf("ReadRLE() direction = %v, want %v", ant.Direction, DirectionTop)
+	}
+	cell, err := ant.CellAt(Point{X: 0, Y: 1})
+	if err != nil || cell.Step.Index != 1 {
+		t.Errorf("ReadRLE() cell above the ant = %v, %v, want colour 1", cell, err)
+	}
+
+	for _, pattern := range []string{
+		"x = 1, y = 1, rule = B3/S23\no!",
+		"x = 1, y = 1, rule = Turmite_120080\nA!",
+		"x = 2, y = 1, rule = Turmite_120080\nCC!",
+		"x = 1, y = 1, rule = Turmite_120080\nC",
+	} {
+		if _, err := ReadRLE(strings.NewReader(pattern), 0); err == nil {
+			t.Errorf("ReadRLE(%q) error = nil, want error", pattern)
+		}
+	}
+}
+
+func TestGollyRule_Turmite(t *testing.T) {
+	fibonacci, err := ParseTurmite("{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+	if err != nil {
+		t.Fatalf("ParseTurmite() error = %v", err)
+	}
+	rule, err := fibonacci.GollyRule()
+	if err != nil || rule != "Turmite_181181121010" {
+		t.Fatalf("GollyRule() = %v, %v, want Turmite_181181121010", rule, err)
+	}
+	got, err := ParseGollyTurmite(rule)
+	if err != nil || got.String() != fibonacci.String() {
+		t.Errorf("ParseGollyTurmite() = %v, %v, want %v", got, err, fibonacci)
+	}
+	if _, err := ParseGollyTurmite("Turmite_181181121"); err == nil {
+		t.Errorf("ParseGollyTurmite() error = nil, want error")
+	}
+
+	ant, err := NewTurmiteAnt(NewBoard(40), fibonacci)
+	if err != nil {
+		t.Fatalf("NewTurmiteAnt() error = %v", err)
+	}
+	for ant.TurmiteState() != 1 {
+		ant.NextN(101)
+	}
+	buffer := &bytes.Buffer{}
+	if err := WriteRLE(buffer, ant); err != nil {
+		t.Fatalf("WriteRLE() error = %v", err)
+	}
+	if !strings.Contains(buffer.String(), "rule = Turmite_181181121010\n") {
+		t.Fatalf("WriteRLE() = %s, want rule Turmite_181181121010", buffer.String())
+	}
+	read, err := ReadRLE(bytes.NewReader(buffer.Bytes()), 20)
+	if err != nil {
+		t.Fatalf("ReadRLE() error = %v", err)
+	}
+	if read.TurmiteState() != 1 || read.Direction != ant.Direction {
+		t.Fatalf("ReadRLE() state %d facing %v, want state 1 facing %v", read.TurmiteState(), read.Direction, ant.Direction)
+	}
+
+	offset := ant.Position.Point
+	ant.NextN(500)
+	read.NextN(500)
+	if read.Position.X != ant.Position.X-offset.X || read.Position.Y != ant.Position.Y-offset.Y {
+		t.Errorf("ReadRLE() ant walks to %v, want %v", read.Position.Point, ant.Position.Point)
+	}
+}
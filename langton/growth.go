@@ -0,0 +1,205 @@
+package langton
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// GrowthSample is the size of the pattern drawn by an ant at a step
+type GrowthSample struct {
+	Step int64 `json:"step"`
+	// Visited is the number of cells visited by the ant
+	Visited int64 `json:"visited"`
+	// Width and Height are the size of the bounding box of the visited cells
+	Width  int64 `json:"width"`
+	Height int64 `json:"height"`
+}
+
+// Area returns the area of the bounding box of the visited cells
+func (sample GrowthSample) Area() int64 {
+	return sample.Width * sample.Height
+}
+
+// BoxCount is the number of boxes of a given side needed to cover the pattern
+type BoxCount struct {
+	Size  int64 `json:"size"`
+	Boxes int64 `json:"boxes"`
+}
+
+// Growth is the time series of the size of the pattern drawn by an ant and the metrics estimated from it
+type Growth struct {
+	Steps   string         `json:"steps"`
+	Samples []GrowthSample `json:"samples"`
+	// Exponent is alpha in visited ~ t^alpha. A step visits at most one new cell so it is up to 1,
+	// reached by highways, and patterns that keep walking over the same area grow slower
+	Exponent float64 `json:"exponent"`
+	// Boxes is the box count of the last pattern for every power of two side
+	Boxes []BoxCount `json:"boxes"`
+	// FractalDimension is the box-counting dimension of the last pattern, 2 for a filled area and 1 for a line
+	FractalDimension float64 `json:"fractal_dimension"`
+}
+
+// NewGrowth creates an empty growth series for the ant
+func NewGrowth(ant *Ant) *Growth {
+	return &Growth{
+		Steps: Steps(ant.steps).String(),
+	}
+}
+
+// Sample appends the current size of the pattern to the series
+func (growth *Growth) Sample(ant *Ant) {
+	var visited int64
+	for _, count := range ant.histogram {
+		visited += count
+	}
+	growth.Samples = append(growth.Samples, GrowthSample{
+		Step:    ant.totalSteps,
+		Visited: visited,
+		Width:   ant.visited.Width(),
+		Height:  ant.visited.Height(),
+	})
+}
+
+// Fit estimates the growth exponent from the samples and the fractal dimension from the current pattern of the ant
+func (growth *Growth) Fit(ant *Ant) {
+	xs, ys := []float64{}, []float64{}
+	for _, sample := range growth.Samples {
+		if sample.Step <= 0 || sample.Visited <= 0 {
+			continue
+		}
+		xs = append(xs, math.Log(float64(sample.Step)))
+		ys = append(ys, math.Log(float64(sample.Visited)))
+	}
+	growth.Exponent = slope(xs, ys)
+	growth.Boxes = ant.BoxCount()
+	growth.FractalDimension = BoxDimension(growth.Boxes)
+}
+
+// GrowthSteps returns up to n steps between 1 and end spaced logarithmically, as power laws are fitted in log scale
+func GrowthSteps(end int64, n int) []int64 {
+	out := []int64{}
+	if end <= 0 || n <= 0 {
+		return out
+	}
+	for i := 0; i < n; i++ {
+		step := end
+		if n > 1 {
+			step = int64(math.Round(math.Pow(float64(end), float64(i)/float64(n-1))))
+		}
+		if len(out) == 0 || step > out[len(out)-1] {
+			out = append(out, step)
+		}
+	}
+	return out
+}
+
+// RecordGrowth moves the ant the given steps sampling the pattern n times, and fits the metrics at the end.
+// If the ant gets stuck the series is fitted up to that step and the error is returned
+func RecordGrowth(ant *Ant, steps int64, n int) (*Growth, error) {
+	growth := NewGrowth(ant)
+	start := ant.totalSteps
+	var err error
+	for _, step := range GrowthSteps(steps, n) {
+		for ant.totalSteps < start+step {
+			_, err = ant.Next()
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+		growth.Sample(ant)
+	}
+	growth.Fit(ant)
+	return growth, err
+}
+
+// BoxCount covers the cells with a colour other than 0 with boxes of side 1, 2, 4... and counts the boxes needed.
+// It stops at the first side that covers the whole pattern with a single box
+func (ant *Ant) BoxCount() []BoxCount {
+	cells, bounds, ok := ant.patternCells()
+	if !ok {
+		return []BoxCount{}
+	}
+	boxes := make(map[Point]struct{}, len(cells))
+	for _, cell := range cells {
+		boxes[Point{X: cell.X - bounds.BottomLeft.X, Y: cell.Y - bounds.BottomLeft.Y}] = struct{}{}
+	}
+	out := []BoxCount{}
+	for size := int64(1); ; size *= 2 {
+		out = append(out, BoxCount{
+			Size:  size,
+			Boxes: int64(len(boxes)),
+		})
+		if len(boxes) == 1 {
+			return out
+		}
+		next := make(map[Point]struct{}, len(boxes)/2)
+		for box := range boxes {
+			next[Point{X: box.X / 2, Y: box.Y / 2}] = struct{}{}
+		}
+		boxes = next
+	}
+}
+
+// BoxDimension estimates the fractal dimension as the slope of log(boxes) against log(1/size).
+// Sides that cover the pattern with less than 4 boxes are ignored, they only measure the bounding box
+func BoxDimension(counts []BoxCount) float64 {
+	xs, ys := []float64{}, []float64{}
+	for _, count := range counts {
+		if count.Boxes < 4 {
+			continue
+		}
+		xs = append(xs, -math.Log(float64(count.Size)))
+		ys = append(ys, math.Log(float64(count.Boxes)))
+	}
+	return slope(xs, ys)
+}
+
+// slope returns the least squares slope of ys against xs, 0 with less than 2 points
+func slope(xs, ys []float64) float64 {
+	n := float64(len(xs))
+	if len(xs) < 2 {
+		return 0
+	}
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// WriteCSV writes a row per sample with the step, the visited cells and the size and area of the bounding box
+func (growth *Growth) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"step", "visited", "width", "height", "area"})
+	for _, sample := range growth.Samples {
+		writer.Write([]string{
+			strconv.FormatInt(sample.Step, 10),
+			strconv.FormatInt(sample.Visited, 10),
+			strconv.FormatInt(sample.Width, 10),
+			strconv.FormatInt(sample.Height, 10),
+			strconv.FormatInt(sample.Area(), 10),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the samples, the box counts and the fitted metrics
+func (growth *Growth) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(growth)
+}
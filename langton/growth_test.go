@@ -0,0 +1,119 @@
+package langton
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBoxDimension(t *testing.T) {
+	steps := StepsFromString("LR")
+	steps.Numerate()
+	tests := []struct {
+		name   string
+		points func(add func(p Point))
+		want   float64
+	}{
+		{
+			name: "square",
+			points: func(add func(p Point)) {
+				for y := int64(0); y < 64; y++ {
+					for x := int64(0); x < 64; x++ {
+						add(Point{X: x, Y: y})
+					}
+				}
+			},
+			want: 2,
+		},
+		{
+			name: "line",
+			points: func(add func(p Point)) {
+				for x := int64(0); x < 64; x++ {
+					add(Point{X: x, Y: 5})
+				}
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := NewSparseBoard(NewBoard(100))
+			tt.points(func(p Point) {
+				board.SetCell(Cell{Point: p, Step: steps[1]})
+			})
+			ant := NewAntOnBoard(board, steps...)
+			counts := ant.BoxCount()
+			if last := counts[len(counts)-1]; last.Boxes != 1 {
+				t.Errorf("BoxCount() last = %v, want a single box", last)
+			}
+			if got := BoxDimension(counts); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("BoxDimension() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrowthSteps(t *testing.T) {
+	got := GrowthSteps(1000, 4)
+	want := []int64{1, 10, 100, 1000}
+	if len(got) != len(want) {
+		t.Fatalf("GrowthSteps() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GrowthSteps() = %v, want %v", got, want)
+		}
+	}
+	if got := GrowthSteps(3, 10); len(got) != 3 {
+		t.Errorf("GrowthSteps(3, 10) = %v, want 3 different steps", got)
+	}
+}
+
+func TestRecordGrowth(t *testing.T) {
+	ant := NewAntFromString(NewBoard(100), "LRRRRRLLR")
+	growth, err := RecordGrowth(ant, 100000, 20)
+	if err != nil {
+		t.Fatalf("RecordGrowth() error = %v", err)
+	}
+	if len(growth.Samples) != 20 || growth.Samples[19].Step != 100000 {
+		t.Fatalf("RecordGrowth() samples = %v, want 20 up to step 100000", growth.Samples)
+	}
+	for i := 1; i < len(growth.Samples); i++ {
+		if growth.Samples[i].Visited < growth.Samples[i-1].Visited {
+			t.Errorf("RecordGrowth() visited cells decrease at step %d", growth.Samples[i].Step)
+		}
+	}
+	if growth.Exponent <= 0 || growth.Exponent > 1 {
+		t.Errorf("RecordGrowth() Exponent = %v, want in (0, 1]", growth.Exponent)
+	}
+	// the rule fills the space
+	if growth.FractalDimension < 1.5 {
+		t.Errorf("RecordGrowth() FractalDimension = %v, want more than 1.5", growth.FractalDimension)
+	}
+
+	buf := &bytes.Buffer{}
+	err = growth.WriteCSV(buf)
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "step,visited,width,height,area" || len(lines) != len(growth.Samples)+1 {
+		t.Errorf("WriteCSV() = %q", buf.String())
+	}
+
+	buf.Reset()
+	err = growth.WriteJSON(buf)
+	if err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	read := &Growth{}
+	err = json.Unmarshal(buf.Bytes(), read)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if read.Steps != "LRRRRRLLR" || read.FractalDimension != growth.FractalDimension || len(read.Samples) != len(growth.Samples) {
+		t.Errorf("WriteJSON() = %s", buf.String())
+	}
+}
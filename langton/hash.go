@@ -0,0 +1,90 @@
+package langton
+
+import "sort"
+
+// Hash returns a Zobrist hash of the ant state, built from the visited cells, the position, the direction and the turmite state.
+// It is updated on every step at constant cost, so it is cheap to use for cycle detection and deduplication.
+// Equal ants always have the same hash, the opposite is true with a very high probability
+func (ant *Ant) Hash() uint64 {
+	hash := ant.boardHash ^ hashAnt(ant.Position.Point, ant.Direction)
+	// ants without turmite are always in the state 0
+	if ant.turmite != nil && ant.turmite.state != 0 {
+		hash ^= mix64(uint64(ant.turmite.state) * 0x8cb92ba72f3d8dd7)
+	}
+	return hash
+}
+
+// hashCell returns the key of a visited cell, the board hash is the xor of the keys of every visited cell
+func hashCell(cell Cell) uint64 {
+	return mix64(uint64(cell.X)*0x9e3779b97f4a7c15 ^
+		uint64(cell.Y)*0xc2b2ae3d27d4eb4f ^
+		uint64(cell.Step.Index+1)*0x165667b19e3779f9)
+}
+
+// hashAnt returns the key of the ant position and direction
+func hashAnt(p Point, direction Direction) uint64 {
+	return mix64(uint64(p.X)*0xd6e8feb86659fd93 ^
+		uint64(p.Y)*0xff51afd7ed558ccd ^
+		uint64(direction+1)*0xc4ceb9fe1a85ec53)
+}
+
+// CellDiff is a point where two boards differ.
+// A step with ActionNone means that the cell has not been visited in that board
+type CellDiff struct {
+	Point Point
+	A     Step
+	B     Step
+}
+
+// Equal returns true if both ants are at the same position and direction and have visited the same cells with the same step indexes.
+// The board type and dimensions are not compared
+func (ant *Ant) Equal(other *Ant) bool {
+	if ant.Hash() != other.Hash() {
+		return false
+	}
+	if ant.Position.Point != other.Position.Point || ant.Direction != other.Direction {
+		return false
+	}
+	return len(ant.Diff(other)) == 0
+}
+
+// Diff returns the cells with a different step index in both boards, sorted row by row from the bottom left corner
+func (ant *Ant) Diff(other *Ant) []CellDiff {
+	diffs := []CellDiff{}
+	ant.Board.Each(func(cell Cell) bool {
+		otherCell, _ := other.Board.Cell(cell.Point)
+		if !sameStep(cell.Step, otherCell.Step) {
+			diffs = append(diffs, CellDiff{
+				Point: cell.Point,
+				A:     cell.Step,
+				B:     otherCell.Step,
+			})
+		}
+		return true
+	})
+	other.Board.Each(func(cell Cell) bool {
+		if _, ok := ant.Board.Cell(cell.Point); !ok {
+			diffs = append(diffs, CellDiff{
+				Point: cell.Point,
+				B:     cell.Step,
+			})
+		}
+		return true
+	})
+	sort.Slice(diffs, func(i, j int) bool {
+		a, b := diffs[i].Point, diffs[j].Point
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return diffs
+}
+
+// sameStep returns true if both steps are visited with the same index, or both are not visited
+func sameStep(a, b Step) bool {
+	if a.Action == ActionNone || b.Action == ActionNone {
+		return a.Action == b.Action
+	}
+	return a.Index == b.Index
+}
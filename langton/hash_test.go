@@ -0,0 +1,63 @@
+package langton
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnt_Hash(t *testing.T) {
+	dense := NewAnt(NewBoard(30), StepsAwesome...)
+	sparse := NewAntOnBoard(NewSparseBoard(NewBoard(40)), StepsFromString(StepsAwesome.String())...)
+	compact := NewAntOnBoard(NewCompactBoard(NewBoard(30), StepsAwesome), StepsFromString(StepsAwesome.String())...)
+	for i := 0; i < 2000; i++ {
+		dense.Next()
+		sparse.Next()
+		compact.Next()
+		if dense.Hash() != sparse.Hash() || dense.Hash() != compact.Hash() {
+			t.Fatalf("step %d: Ant.Hash() = %x, %x, %x, want equal", i, dense.Hash(), sparse.Hash(), compact.Hash())
+		}
+	}
+	if !dense.Equal(sparse) {
+		t.Errorf("Ant.Equal() = false, want true")
+	}
+
+	fresh := NewAnt(NewBoard(30), StepsFromString(StepsAwesome.String())...)
+	copyBoard(fresh.Board, dense.Board)
+	fresh.Position = dense.Position
+	fresh.Direction = dense.Direction
+	fresh.retrack()
+	if fresh.Hash() != dense.Hash() {
+		t.Errorf("Ant.Hash() = %x, want %x", dense.Hash(), fresh.Hash())
+	}
+
+	dense.Direction = dense.Direction.Turn(ActionTurnRight)
+	if dense.Hash() == sparse.Hash() {
+		t.Errorf("Ant.Hash() does not depend on the direction")
+	}
+	if dense.Equal(sparse) {
+		t.Errorf("Ant.Equal() = true, want false")
+	}
+}
+
+func TestAnt_Diff(t *testing.T) {
+	a := NewAntFromString(NewBoard(5), "LR")
+	b := NewAntFromString(NewBoard(5), "LR")
+	a.NextN(3)
+	b.NextN(3)
+	if diffs := a.Diff(b); len(diffs) != 0 {
+		t.Errorf("Ant.Diff() = %v, want none", diffs)
+	}
+
+	b.setStep(Point{X: 0, Y: 0}, b.steps[0])
+	b.setStep(Point{X: 3, Y: 3}, b.steps[1])
+	want := []CellDiff{
+		{Point: Point{X: 0, Y: 0}, A: a.steps[1], B: b.steps[0]},
+		{Point: Point{X: 3, Y: 3}, B: b.steps[1]},
+	}
+	if got := a.Diff(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ant.Diff() = %v, want %v", got, want)
+	}
+	if a.Equal(b) {
+		t.Errorf("Ant.Equal() = true, want false")
+	}
+}
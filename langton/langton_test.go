@@ -1,6 +1,7 @@
 package langton
 
 import (
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -347,6 +348,39 @@ func TestStepsFromString(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Stochastic",
+			args: args{
+				steps: "[L0.9R]R",
+			},
+			want: Steps{
+				Step{
+					Action:      ActionTurnLeft,
+					Alternative: ActionTurnRight,
+					Probability: 0.9,
+				},
+				Step{
+					Action: ActionTurnRight,
+				},
+			},
+		},
+		{
+			name: "Unknown action",
+			args: args{
+				steps: "LXR",
+			},
+			want: Steps{
+				Step{
+					Action: ActionTurnLeft,
+				},
+				Step{
+					Action: ActionNone,
+				},
+				Step{
+					Action: ActionTurnRight,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -409,6 +443,22 @@ func TestDimensions_IndexOf(t *testing.T) {
 			fields: NewDimensions(-1, -1, 1, 1),
 			want:   1,
 		},
+		{
+			name: "step up wide",
+			args: args{
+				p: Point{-2, 0},
+			},
+			fields: NewDimensions(-2, -1, 2, 1),
+			want:   5,
+		},
+		{
+			name: "last corner wide",
+			args: args{
+				p: Point{2, 1},
+			},
+			fields: NewDimensions(-2, -1, 2, 1),
+			want:   14,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -426,6 +476,19 @@ func TestDimensions_IndexOf(t *testing.T) {
 	}
 }
 
+func TestDimensions_WidthHeight(t *testing.T) {
+	dim := NewDimensions(0, 0, 9, 4)
+	if dim.Width() != 10 || dim.Height() != 5 || dim.String() != "10x5" {
+		t.Errorf("Dimensions = %d, %d, %s, want 10, 5, 10x5", dim.Width(), dim.Height(), dim.String())
+	}
+	for i := 0; i < int(dim.Size); i++ {
+		p := dim.pointOf(i)
+		if !dim.Contains(p) || dim.indexOf(p) != i {
+			t.Fatalf("Dimensions.pointOf(%d) = %s, indexOf = %d", i, p, dim.indexOf(p))
+		}
+	}
+}
+
 func TestAnt_Grow(t *testing.T) {
 	type args struct {
 		antSteps    int
@@ -498,3 +561,99 @@ func TestAnt_Grow_And_Walk(t *testing.T) {
 		t.Errorf("Ant.Grow() doesn't keep shape\n%v\nwant\n%v", grow, static)
 	}
 }
+
+func TestParseSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   string
+		want    Steps
+		wantErr bool
+	}{
+		{
+			name:  "deterministic",
+			steps: "LSR",
+			want: Steps{
+				Step{Action: ActionTurnLeft},
+				Step{Action: ActionStraight},
+				Step{Action: ActionTurnRight},
+			},
+		},
+		{
+			name:  "stochastic",
+			steps: "[L0.9R]R",
+			want: Steps{
+				Step{Action: ActionTurnLeft, Alternative: ActionTurnRight, Probability: 0.9},
+				Step{Action: ActionTurnRight},
+			},
+		},
+		{
+			name:    "unknown action",
+			steps:   "LX",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed bracket",
+			steps:   "[L0.5R",
+			wantErr: true,
+		},
+		{
+			name:    "invalid probability",
+			steps:   "[L1.5R]",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			steps:   "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSteps(tt.steps)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSteps() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSteps() = %v, want %v", got, tt.want)
+			}
+			if err == nil && got.String() != tt.steps {
+				t.Errorf("Steps.String() = %s, want %s", got.String(), tt.steps)
+			}
+		})
+	}
+}
+
+func TestAnt_Stochastic(t *testing.T) {
+	run := func(seed int64) string {
+		ant := NewAntFromString(NewBoard(20), "[L0.9R][R0.8S]")
+		ant.Seed(seed)
+		ant.SetNoise(FlipNoise{Every: 10, Radius: 2})
+		ant.NextN(500)
+		return ant.String()
+	}
+	if run(1) != run(1) {
+		t.Errorf("Ants with the same seed must produce the same board")
+	}
+	if run(1) == run(2) {
+		t.Errorf("Ants with different seeds should produce different boards")
+	}
+
+	certain := NewAntFromString(NewBoard(20), "[L1R][R1L]")
+	certain.NextN(500)
+	deterministic := NewAntFromString(NewBoard(20), "LR")
+	deterministic.NextN(500)
+	if certain.String() != deterministic.String() {
+		t.Errorf("Steps with probability 1 must behave as deterministic steps")
+	}
+}
+
+func TestAnt_SetNoise(t *testing.T) {
+	ant := NewAntFromString(NewBoard(20), "LR")
+	if err := ant.SetNoise(FlipNoise{Every: 10, Radius: -1}); !errors.Is(err, ErrInvalidNoise) {
+		t.Fatalf("Ant.SetNoise() error = %v, want %v", err, ErrInvalidNoise)
+	}
+	ant.NextN(100)
+	if err := ant.SetNoise(FlipNoise{Every: 10, Radius: 0}); err != nil {
+		t.Errorf("Ant.SetNoise() error = %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidNoise = errors.New("Invalid noise")
+
+// Noise perturbs the board while the ant walks
+type Noise interface {
+	// Apply is called after every successful step with the ant random source
+	Apply(ant *Ant, random *Random)
+	// Validate checks the parameters before the noise is set to an ant
+	Validate() error
+}
+
+// FlipNoise sets a random cell around the ant to a random step every N steps
+type FlipNoise struct {
+	// Every is the number of steps between flips
+	Every int64
+	// Radius is the maximum distance in each axis from the ant to the flipped cell
+	Radius int64
+}
+
+// Validate checks that the radius is not negative
+func (noise FlipNoise) Validate() error {
+	if noise.Radius < 0 {
+		return fmt.Errorf("%w: negative radius %d", ErrInvalidNoise, noise.Radius)
+	}
+	return nil
+}
+
+// Apply flips a cell if the ant total steps is a multiple of Every.
+// Cells out of the board are ignored
+func (noise FlipNoise) Apply(ant *Ant, random *Random) {
+	if noise.Every <= 0 || ant.TotalSteps()%noise.Every != 0 {
+		return
+	}
+	side := 2*noise.Radius + 1
+	p := Point{
+		X: ant.Position.X - noise.Radius + int64(random.Intn(int(side))),
+		Y: ant.Position.Y - noise.Radius + int64(random.Intn(int(side))),
+	}
+	step := ant.steps[random.Intn(len(ant.steps))]
+	ant.setStep(p, step)
+}
@@ -0,0 +1,231 @@
+package langton
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Symmetry is a transformation of the board that leaves a symmetric pattern unchanged
+type Symmetry int
+
+const (
+	// SymmetryHorizontal mirrors the pattern across a horizontal axis, top and bottom are swapped
+	SymmetryHorizontal Symmetry = iota
+	// SymmetryVertical mirrors the pattern across a vertical axis, left and right are swapped
+	SymmetryVertical
+	// SymmetryDiagonal mirrors the pattern across the axis going up to the right
+	SymmetryDiagonal
+	// SymmetryAntiDiagonal mirrors the pattern across the axis going down to the right
+	SymmetryAntiDiagonal
+	// SymmetryRotation90 rotates the pattern a quarter turn
+	SymmetryRotation90
+	// SymmetryRotation180 rotates the pattern half a turn
+	SymmetryRotation180
+	SymmetryInvalid
+)
+
+var symmetryNames = [SymmetryInvalid]string{
+	"horizontal",
+	"vertical",
+	"diagonal",
+	"antidiagonal",
+	"rotation90",
+	"rotation180",
+}
+
+// String returns the name of the symmetry
+func (symmetry Symmetry) String() string {
+	if symmetry < 0 || symmetry >= SymmetryInvalid {
+		return "invalid"
+	}
+	return symmetryNames[symmetry]
+}
+
+// Apply returns the image of p around the given center.
+// The center is given with twice its coordinates, so axes and centres between cells are integers.
+// ok is false if the image falls between cells, that happens to diagonals and quarter turns when the center is
+// in the middle of a cell in one axis and between cells in the other
+func (symmetry Symmetry) Apply(p Point, center Point) (image Point, ok bool) {
+	sum, diff := center.X+center.Y, center.X-center.Y
+	switch symmetry {
+	case SymmetryHorizontal:
+		return Point{X: p.X, Y: center.Y - p.Y}, true
+	case SymmetryVertical:
+		return Point{X: center.X - p.X, Y: p.Y}, true
+	case SymmetryRotation180:
+		return Point{X: center.X - p.X, Y: center.Y - p.Y}, true
+	}
+	if sum%2 != 0 {
+		return Point{}, false
+	}
+	switch symmetry {
+	case SymmetryDiagonal:
+		return Point{X: diff/2 + p.Y, Y: -diff/2 + p.X}, true
+	case SymmetryAntiDiagonal:
+		return Point{X: sum/2 - p.Y, Y: sum/2 - p.X}, true
+	case SymmetryRotation90:
+		return Point{X: sum/2 - p.Y, Y: -diff/2 + p.X}, true
+	default:
+		panic("invalid symmetry")
+	}
+}
+
+// SymmetryReport is how well the pattern of an ant matches a symmetry
+type SymmetryReport struct {
+	Symmetry Symmetry
+	// Center is twice the coordinates of the axis or rotation centre, so centres between cells are integers
+	Center Point
+	// Score is the fraction of coloured cells whose image has the same colour, 1 is a perfect symmetry
+	Score float64
+	// Step is the total steps of the ant when the pattern was analysed
+	Step int64
+}
+
+func (report SymmetryReport) String() string {
+	return fmt.Sprintf("%s around (%g, %g) score %.3f at step %d",
+		report.Symmetry,
+		float64(report.Center.X)/2,
+		float64(report.Center.Y)/2,
+		report.Score,
+		report.Step,
+	)
+}
+
+// colour returns the step index of the cell at p, 0 for cells that have not been visited.
+// Cells with index 0 look the same as the background, so they are not part of the pattern
+func (ant *Ant) colour(p Point) int {
+	cell, ok := ant.Board.Cell(p)
+	if !ok {
+		return 0
+	}
+	return cell.Step.Index
+}
+
+// patternCells returns the cells with a colour other than 0 and their bounding box
+func (ant *Ant) patternCells() ([]Cell, Dimensions, bool) {
+	cells := []Cell{}
+	var bounds Dimensions
+	ant.Region(ant.visited, func(cell Cell) bool {
+		if cell.Step.Index == 0 {
+			return true
+		}
+		if len(cells) == 0 {
+			bounds = NewDimensions(cell.X, cell.Y, cell.X, cell.Y)
+		}
+		bounds = bounds.extend(cell.Point)
+		cells = append(cells, cell)
+		return true
+	})
+	return cells, bounds, len(cells) != 0
+}
+
+// symmetryScore returns the fraction of cells whose image has the same colour
+func (ant *Ant) symmetryScore(cells []Cell, symmetry Symmetry, center Point) float64 {
+	if len(cells) == 0 {
+		return 1
+	}
+	matches := 0
+	for _, cell := range cells {
+		image, ok := symmetry.Apply(cell.Point, center)
+		if !ok {
+			return 0
+		}
+		if ant.colour(image) == cell.Step.Index {
+			matches++
+		}
+	}
+	return float64(matches) / float64(len(cells))
+}
+
+// SymmetryScore returns the fraction of coloured cells whose image by the symmetry around the center has the same colour.
+// The center is given with twice its coordinates as in Symmetry.Apply
+func (ant *Ant) SymmetryScore(symmetry Symmetry, center Point) float64 {
+	cells, _, _ := ant.patternCells()
+	return ant.symmetryScore(cells, symmetry, center)
+}
+
+// Symmetries measures every symmetry of the coloured cells and returns them from the best score to the worst.
+// The centre is searched around the middle of the bounding box of the pattern, half a cell in each direction,
+// so patterns with a few broken cells at the border are still found. Each call scans the whole pattern several times
+func (ant *Ant) Symmetries() []SymmetryReport {
+	cells, bounds, ok := ant.patternCells()
+	reports := make([]SymmetryReport, SymmetryInvalid)
+	for s := range reports {
+		reports[s] = SymmetryReport{
+			Symmetry: Symmetry(s),
+			Step:     ant.totalSteps,
+		}
+	}
+	if !ok {
+		for s := range reports {
+			reports[s].Center = Point{X: 2 * ant.Position.X, Y: 2 * ant.Position.Y}
+			reports[s].Score = 1
+		}
+		return reports
+	}
+	middle := Point{
+		X: bounds.BottomLeft.X + bounds.TopRight.X,
+		Y: bounds.BottomLeft.Y + bounds.TopRight.Y,
+	}
+	for s := range reports {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for dx := int64(-1); dx <= 1; dx++ {
+				center := Point{X: middle.X + dx, Y: middle.Y + dy}
+				score := ant.symmetryScore(cells, Symmetry(s), center)
+				// the middle of the bounding box wins the ties
+				if score > reports[s].Score || (dx == 0 && dy == 0 && score == reports[s].Score) {
+					reports[s].Score = score
+					reports[s].Center = center
+				}
+			}
+		}
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Score > reports[j].Score
+	})
+	return reports
+}
+
+// SymmetryTracker finds the first step at which the pattern drawn by an ant becomes symmetric
+type SymmetryTracker struct {
+	// Threshold is the minimum score, 1 requires a perfect symmetry
+	Threshold float64
+	// MinCells ignores patterns with fewer coloured cells, small patterns are often symmetric by chance
+	MinCells int64
+	// Every is the number of steps between checks, each check scans the whole pattern. 0 checks every step
+	Every int64
+}
+
+// Run moves the ant up to the given steps and stops at the first check where a symmetry reaches the threshold.
+// found is false if no symmetric pattern appeared, err is returned if the ant gets stuck
+func (tracker *SymmetryTracker) Run(ant *Ant, steps int64) (report SymmetryReport, found bool, err error) {
+	every := tracker.Every
+	if every <= 0 {
+		every = 1
+	}
+	end := ant.totalSteps + steps
+	for {
+		if ant.totalSteps%every == 0 && tracker.coloured(ant) >= tracker.MinCells {
+			best := ant.Symmetries()[0]
+			if best.Score >= tracker.Threshold {
+				return best, true, nil
+			}
+		}
+		if ant.totalSteps >= end {
+			return SymmetryReport{}, false, nil
+		}
+		_, err = ant.Next()
+		if err != nil {
+			return SymmetryReport{}, false, err
+		}
+	}
+}
+
+// coloured returns the number of cells with a colour other than 0
+func (tracker *SymmetryTracker) coloured(ant *Ant) int64 {
+	var total int64
+	for i := 1; i < len(ant.histogram); i++ {
+		total += ant.histogram[i]
+	}
+	return total
+}
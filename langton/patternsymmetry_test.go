@@ -0,0 +1,88 @@
+package langton
+
+import "testing"
+
+func TestSymmetry_Apply(t *testing.T) {
+	centers := []Point{{X: 0, Y: 0}, {X: 3, Y: -1}, {X: -4, Y: 6}, {X: 1, Y: 2}}
+	p := Point{X: 5, Y: -7}
+	for symmetry := Symmetry(0); symmetry < SymmetryInvalid; symmetry++ {
+		turns := 2
+		if symmetry == SymmetryRotation90 {
+			turns = 4
+		}
+		for _, center := range centers {
+			image, ok := p, true
+			for i := 0; i < turns && ok; i++ {
+				image, ok = symmetry.Apply(image, center)
+			}
+			if !ok {
+				if (center.X+center.Y)%2 == 0 {
+					t.Errorf("%s.Apply() around %s falls between cells", symmetry, center)
+				}
+				continue
+			}
+			if image != p {
+				t.Errorf("%s.Apply() %d times around %s = %s, want %s", symmetry, turns, center, image, p)
+			}
+		}
+	}
+}
+
+func TestAnt_Symmetries(t *testing.T) {
+	steps := StepsFromString("LRR")
+	steps.Numerate()
+	board := NewDenseBoard(NewBoard(10))
+	// an L shape is only symmetric across the diagonal through its corner
+	for _, p := range []Point{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}} {
+		board.SetCell(Cell{Point: p, Step: steps[1]})
+	}
+	board.SetCell(Cell{Point: Point{X: 3, Y: 3}, Step: steps[2]})
+	ant := NewAntOnBoard(board, steps...)
+
+	reports := ant.Symmetries()
+	if len(reports) != int(SymmetryInvalid) {
+		t.Fatalf("Symmetries() returned %d reports, want %d", len(reports), SymmetryInvalid)
+	}
+	best := reports[0]
+	if best.Symmetry != SymmetryDiagonal || best.Score != 1 || best.Center != (Point{X: 6, Y: 6}) {
+		t.Errorf("Symmetries()[0] = %s, want diagonal around (3, 3) score 1", best)
+	}
+	if score := ant.SymmetryScore(SymmetryVertical, best.Center); score == 1 {
+		t.Errorf("SymmetryScore(vertical) = %v, want less than 1", score)
+	}
+	for _, report := range reports[1:] {
+		if report.Score > best.Score {
+			t.Errorf("Symmetries() are not sorted, %s after %s", report, best)
+		}
+	}
+}
+
+func TestSymmetryTracker_Run(t *testing.T) {
+	tests := []struct {
+		steps    string
+		symmetry Symmetry
+		want     int64
+	}{
+		{steps: "LLRR", symmetry: SymmetryHorizontal, want: 376},
+		{steps: "LR", symmetry: SymmetryRotation180, want: 368},
+	}
+	for _, tt := range tests {
+		t.Run(tt.steps, func(t *testing.T) {
+			ant := NewAntFromString(NewBoard(100), tt.steps)
+			tracker := &SymmetryTracker{
+				Threshold: 1,
+				MinCells:  50,
+			}
+			report, found, err := tracker.Run(ant, 10000)
+			if err != nil || !found {
+				t.Fatalf("SymmetryTracker.Run() found = %v, error = %v", found, err)
+			}
+			if report.Symmetry != tt.symmetry || report.Step != tt.want {
+				t.Errorf("SymmetryTracker.Run() = %s, want %s at step %d", report, tt.symmetry, tt.want)
+			}
+			if ant.TotalSteps() != report.Step {
+				t.Errorf("SymmetryTracker.Run() stopped at step %d, want %d", ant.TotalSteps(), report.Step)
+			}
+		})
+	}
+}
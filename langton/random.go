@@ -0,0 +1,50 @@
+package langton
+
+// Random is a seedable source of random numbers.
+// It is a splitmix64 generator so the whole state is a single number that can be saved and restored
+type Random struct {
+	state uint64
+}
+
+// NewRandom creates a Random source with the given seed
+func NewRandom(seed int64) *Random {
+	return &Random{
+		state: uint64(seed),
+	}
+}
+
+// Seed resets the source to the given seed
+func (random *Random) Seed(seed int64) {
+	random.state = uint64(seed)
+}
+
+// Uint64 returns a pseudo-random 64-bit value
+func (random *Random) Uint64() uint64 {
+	random.state += 0x9e3779b97f4a7c15
+	return mix64(random.state)
+}
+
+// mix64 is the splitmix64 finalizer, it scrambles the bits of z
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 returns a non-negative pseudo-random 63-bit integer
+func (random *Random) Int63() int64 {
+	return int64(random.Uint64() >> 1)
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0)
+func (random *Random) Float64() float64 {
+	return float64(random.Uint64()>>11) / (1 << 53)
+}
+
+// Intn returns a pseudo-random number in [0,n), it panics if n <= 0
+func (random *Random) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	return int(random.Uint64() % uint64(n))
+}
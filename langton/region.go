@@ -0,0 +1,54 @@
+package langton
+
+// Region calls fn for every visited cell inside the given dimensions, row by row from the bottom left corner,
+// until fn returns false. Only the part of the region covered by the visited cells is scanned
+func (ant *Ant) Region(region Dimensions, fn func(cell Cell) bool) {
+	area, ok := region.Intersect(ant.visited)
+	if !ok {
+		return
+	}
+	for y := area.BottomLeft.Y; y <= area.TopRight.Y; y++ {
+		for x := area.BottomLeft.X; x <= area.TopRight.X; x++ {
+			cell, ok := ant.Board.Cell(Point{X: x, Y: y})
+			if !ok {
+				continue
+			}
+			if !fn(cell) {
+				return
+			}
+		}
+	}
+}
+
+// Histogram returns the number of visited cells for each step index.
+// It is kept up to date while the ant moves, so it is cheap to call on large boards
+func (ant *Ant) Histogram() []int64 {
+	out := make([]int64, len(ant.histogram))
+	copy(out, ant.histogram)
+	return out
+}
+
+// HistogramIn returns the number of visited cells for each step index inside the given dimensions
+func (ant *Ant) HistogramIn(region Dimensions) []int64 {
+	out := make([]int64, len(ant.steps))
+	ant.Region(region, func(cell Cell) bool {
+		out[cell.Step.Index]++
+		return true
+	})
+	return out
+}
+
+// Neighbours returns the cells around p in the order given by Neighbourhood.Points facing DirectionTop.
+// Cells that have never been visited or are out of the board have ActionNone
+func (ant *Ant) Neighbours(p Point, neighbourhood Neighbourhood) []Cell {
+	points := neighbourhood.Points(p, DirectionTop)
+	cells := make([]Cell, len(points))
+	for i, point := range points {
+		cell, ok := ant.Board.Cell(point)
+		if !ok {
+			cell = Cell{Point: point}
+		}
+		cells[i] = cell
+	}
+	return cells
+}
@@ -0,0 +1,88 @@
+package langton
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnt_Histogram(t *testing.T) {
+	boards := map[string]Board{
+		"dense":   NewDenseBoard(NewBoard(30)),
+		"sparse":  NewSparseBoard(NewBoard(30)),
+		"compact": NewCompactBoard(NewBoard(30), StepsAwesome),
+	}
+	for name, board := range boards {
+		t.Run(name, func(t *testing.T) {
+			ant := NewAntOnBoard(board, StepsFromString(StepsAwesome.String())...)
+			ant.SetNoise(&FlipNoise{Every: 7, Radius: 3})
+			ant.NextN(3000)
+			if got, want := ant.Histogram(), countSteps(ant); !reflect.DeepEqual(got, want) {
+				t.Errorf("Ant.Histogram() = %v, want %v", got, want)
+			}
+			if got, want := ant.HistogramIn(ant.Dimensions()), countSteps(ant); !reflect.DeepEqual(got, want) {
+				t.Errorf("Ant.HistogramIn() = %v, want %v", got, want)
+			}
+
+			ant.SetSteps(StepsFromString("LR"), MapModulo)
+			if got, want := ant.Histogram(), countSteps(ant); !reflect.DeepEqual(got, want) {
+				t.Errorf("Ant.Histogram() after SetSteps = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAnt_Region(t *testing.T) {
+	ant := NewAnt(NewBoard(30), StepsAwesome...)
+	ant.NextN(3000)
+
+	region := NewDimensions(-3, -2, 4, 5)
+	want := []Cell{}
+	ant.Board.Each(func(cell Cell) bool {
+		if region.Contains(cell.Point) {
+			want = append(want, cell)
+		}
+		return true
+	})
+	got := []Cell{}
+	ant.Region(region, func(cell Cell) bool {
+		got = append(got, cell)
+		return true
+	})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Ant.Region() = %v, want %v", got, want)
+	}
+
+	count := 0
+	ant.Region(region, func(cell Cell) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("Ant.Region() visited %d cells after returning false, want 1", count)
+	}
+}
+
+func TestAnt_Neighbours(t *testing.T) {
+	ant := NewAntFromString(NewBoard(1), "LR")
+	ant.NextN(2)
+
+	got := ant.Neighbours(Point{X: 0, Y: 1}, NeighbourhoodVonNeumann)
+	want := []Cell{
+		{Point: Point{X: 0, Y: 2}},
+		{Point: Point{X: 1, Y: 1}},
+		{Point: Point{X: 0, Y: 0}, Step: Step{Index: 1, Action: ActionTurnRight}},
+		{Point: Point{X: -1, Y: 1}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Ant.Neighbours() = %v, want %v", got, want)
+	}
+}
+
+func countSteps(ant *Ant) []int64 {
+	histogram := make([]int64, len(ant.steps))
+	ant.Board.Each(func(cell Cell) bool {
+		histogram[cell.Step.Index]++
+		return true
+	})
+	return histogram
+}
@@ -0,0 +1,221 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColorMapping returns the index in the new steps for a cell that had the given index in the old steps.
+// The result must be in the range [0, len(to))
+type ColorMapping func(index int, from, to Steps) int
+
+// MapModulo keeps the index, wrapping around if the new steps are shorter
+func MapModulo(index int, from, to Steps) int {
+	return index % len(to)
+}
+
+// MapReset sets every visited cell to the first step
+func MapReset(index int, from, to Steps) int {
+	return 0
+}
+
+// MapProportional keeps the relative position of the index in the cycle
+func MapProportional(index int, from, to Steps) int {
+	return index * len(to) / len(from)
+}
+
+// Phase is a rule followed during a number of steps
+type Phase struct {
+	Steps Steps
+	// Duration is the number of steps of the phase, 0 lasts forever
+	Duration int64
+}
+
+// Schedule is a sequence of rules that the ant follows one after another
+type Schedule struct {
+	Phases []Phase
+	// Loop starts again from the first phase after the last one
+	Loop bool
+	// Mapping translates the cells when the rule changes, MapModulo if nil
+	Mapping ColorMapping
+}
+
+var ErrInvalidSchedule = errors.New("Invalid schedule")
+
+// ParseSchedule parses a comma separated list of phases written as steps:duration.
+// The duration can be omitted in the last phase to make it last forever, "LR:10000,LLRR:5000,LR"
+func ParseSchedule(schedule string) (*Schedule, error) {
+	out := &Schedule{}
+	for _, phase := range strings.Split(schedule, ",") {
+		phase = strings.TrimSpace(phase)
+		parts := strings.Split(phase, ":")
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("%w: phase %q", ErrInvalidSchedule, phase)
+		}
+		steps, err := ParseSteps(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		var duration int64
+		if len(parts) == 2 {
+			duration, err = strconv.ParseInt(parts[1], 10, 64)
+			if err != nil || duration <= 0 {
+				return nil, fmt.Errorf("%w: invalid duration in %q", ErrInvalidSchedule, phase)
+			}
+		}
+		out.Phases = append(out.Phases, Phase{
+			Steps:    steps,
+			Duration: duration,
+		})
+	}
+	return out, out.Validate()
+}
+
+// Validate checks that the phases can be followed
+func (schedule *Schedule) Validate() error {
+	if len(schedule.Phases) == 0 {
+		return fmt.Errorf("%w: no phases", ErrInvalidSchedule)
+	}
+	for i, phase := range schedule.Phases {
+		if len(phase.Steps) == 0 {
+			return fmt.Errorf("%w: phase %d has no steps", ErrInvalidSchedule, i)
+		}
+		if phase.Duration < 0 {
+			return fmt.Errorf("%w: phase %d has a negative duration", ErrInvalidSchedule, i)
+		}
+		if phase.Duration == 0 && (i != len(schedule.Phases)-1 || schedule.Loop) {
+			return fmt.Errorf("%w: only the last phase of a schedule without loop can last forever", ErrInvalidSchedule)
+		}
+	}
+	return nil
+}
+
+// MaxColors returns the length of the longest rule in the schedule
+func (schedule *Schedule) MaxColors() int {
+	max := 0
+	for _, phase := range schedule.Phases {
+		if len(phase.Steps) > max {
+			max = len(phase.Steps)
+		}
+	}
+	return max
+}
+
+// String returns the schedule in the format accepted by ParseSchedule
+func (schedule *Schedule) String() string {
+	phases := make([]string, len(schedule.Phases))
+	for i, phase := range schedule.Phases {
+		phases[i] = phase.Steps.String()
+		if phase.Duration != 0 {
+			phases[i] += ":" + strconv.FormatInt(phase.Duration, 10)
+		}
+	}
+	return strings.Join(phases, ",")
+}
+
+func (schedule *Schedule) mapping() ColorMapping {
+	if schedule.Mapping == nil {
+		return MapModulo
+	}
+	return schedule.Mapping
+}
+
+// scheduleState tracks the progress of an ant through a Schedule
+type scheduleState struct {
+	schedule   *Schedule
+	phase      int
+	nextSwitch int64
+}
+
+// SetSchedule makes the ant follow the schedule starting from the first phase, nil removes it.
+// An ant with a sensing rule can only follow phases with as many colours as the rule supports
+func (ant *Ant) SetSchedule(schedule *Schedule) error {
+	if schedule == nil {
+		ant.schedule = nil
+		return nil
+	}
+	err := schedule.Validate()
+	if err != nil {
+		return err
+	}
+	if ant.turmite != nil {
+		return fmt.Errorf("%w: a turmite can not follow a schedule", ErrInvalidSchedule)
+	}
+	if ant.sensing != nil && schedule.MaxColors() > len(sensingDigits) {
+		return fmt.Errorf("%w: sensing rules support up to %d colours", ErrInvalidSchedule, len(sensingDigits))
+	}
+	ant.schedule = &scheduleState{
+		schedule: schedule,
+	}
+	ant.startPhase(0)
+	return nil
+}
+
+// Phase returns the index of the schedule phase being followed, -1 without schedule
+func (ant *Ant) Phase() int {
+	if ant.schedule == nil {
+		return -1
+	}
+	return ant.schedule.phase
+}
+
+// SetSteps replaces the rule followed by the ant, the visited cells are translated with the given mapping
+func (ant *Ant) SetSteps(steps Steps, mapping ColorMapping) {
+	from := ant.steps
+	to := make(Steps, len(steps))
+	copy(to, steps)
+	to.Numerate()
+
+	cells := []Cell{}
+	ant.Board.Each(func(cell Cell) bool {
+		cells = append(cells, cell)
+		return true
+	})
+	if board, ok := ant.Board.(stepsBoard); ok {
+		board.SetSteps(to)
+	}
+	// the histogram and the hash are rebuilt once every cell is translated
+	ant.untracked = true
+	for _, cell := range cells {
+		index := mapping(cell.Step.Index, from, to)
+		if index < 0 || index >= len(to) {
+			panic("ColorMapping returned an index out of range")
+		}
+		cell.Step = to[index]
+		ant.setCell(cell)
+	}
+	ant.steps = to
+	ant.untracked = false
+	ant.retrack()
+}
+
+// startPhase switches the ant to the rule of the given phase
+func (ant *Ant) startPhase(phase int) {
+	state := ant.schedule
+	current := state.schedule.Phases[phase]
+	state.phase = phase
+	state.nextSwitch = -1
+	if current.Duration != 0 {
+		state.nextSwitch = ant.totalSteps + current.Duration
+	}
+	ant.SetSteps(current.Steps, state.schedule.mapping())
+}
+
+// updateSchedule moves to the next phase when the current one is completed
+func (ant *Ant) updateSchedule() {
+	state := ant.schedule
+	if state == nil || state.nextSwitch != ant.totalSteps {
+		return
+	}
+	next := state.phase + 1
+	if next == len(state.schedule.Phases) {
+		if !state.schedule.Loop {
+			state.nextSwitch = -1
+			return
+		}
+		next = 0
+	}
+	ant.startPhase(next)
+}
@@ -0,0 +1,113 @@
+package langton
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnt_SetSchedule(t *testing.T) {
+	schedule, err := ParseSchedule("LR:100,LLRR:50,RLLLLRRRLLL")
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+	if schedule.String() != "LR:100,LLRR:50,RLLLLRRRLLL" {
+		t.Errorf("Schedule.String() = %s", schedule)
+	}
+
+	scheduled := NewAntFromString(NewBoard(30), "L")
+	err = scheduled.SetSchedule(schedule)
+	if err != nil {
+		t.Fatalf("Ant.SetSchedule() error = %v", err)
+	}
+	scheduled.NextN(300)
+
+	manual := NewAntFromString(NewBoard(30), "LR")
+	manual.NextN(100)
+	manual.SetSteps(StepsFromString("LLRR"), MapModulo)
+	manual.NextN(50)
+	manual.SetSteps(StepsFromString("RLLLLRRRLLL"), MapModulo)
+	manual.NextN(150)
+
+	if scheduled.String() != manual.String() {
+		t.Errorf("scheduled ant = \n%s, want \n%s", scheduled, manual)
+	}
+	if scheduled.Phase() != 2 {
+		t.Errorf("Ant.Phase() = %d, want %d", scheduled.Phase(), 2)
+	}
+}
+
+func TestAnt_SetSchedule_Loop(t *testing.T) {
+	ant := NewAntFromString(NewBoard(30), "LR")
+	err := ant.SetSchedule(&Schedule{
+		Phases: []Phase{
+			{Steps: StepsFromString("LR"), Duration: 10},
+			{Steps: StepsFromString("LLRR"), Duration: 10},
+		},
+		Loop:    true,
+		Mapping: MapReset,
+	})
+	if err != nil {
+		t.Fatalf("Ant.SetSchedule() error = %v", err)
+	}
+	phases := []int{}
+	for i := 0; i < 4; i++ {
+		ant.NextN(10)
+		phases = append(phases, ant.Phase())
+	}
+	want := []int{1, 0, 1, 0}
+	for i := range want {
+		if phases[i] != want[i] {
+			t.Errorf("Ant.Phase() = %v, want %v", phases, want)
+			break
+		}
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		wantErr  bool
+	}{
+		{name: "single rule", schedule: "LR"},
+		{name: "forever in the middle", schedule: "LR,LLRR:10", wantErr: true},
+		{name: "invalid duration", schedule: "LR:-1", wantErr: true},
+		{name: "invalid steps", schedule: "LX:10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.schedule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnt_SetSchedule_Sensing(t *testing.T) {
+	rule, err := ParseSensingRule("ahead *1:S")
+	if err != nil {
+		t.Fatalf("ParseSensingRule() error = %v", err)
+	}
+	long, err := ParseSchedule("LR:10," + strings.Repeat("LR", 20))
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+
+	ant := NewAntFromString(NewBoard(10), "LR")
+	if err := ant.SetSensingRule(rule); err != nil {
+		t.Fatalf("Ant.SetSensingRule() error = %v", err)
+	}
+	if err := ant.SetSchedule(long); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("Ant.SetSchedule() error = %v, want %v", err, ErrInvalidSchedule)
+	}
+
+	ant = NewAntFromString(NewBoard(10), "LR")
+	if err := ant.SetSchedule(long); err != nil {
+		t.Fatalf("Ant.SetSchedule() error = %v", err)
+	}
+	if err := ant.SetSensingRule(rule); !errors.Is(err, ErrInvalidSensingRule) {
+		t.Errorf("Ant.SetSensingRule() error = %v, want %v", err, ErrInvalidSensingRule)
+	}
+}
@@ -0,0 +1,268 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Neighbourhood defines the cells read by a sensing ant besides its own
+type Neighbourhood int
+
+const (
+	// NeighbourhoodAhead reads the cell in front of the ant
+	NeighbourhoodAhead Neighbourhood = iota
+	// NeighbourhoodSides reads the cells at the left and at the right of the ant
+	NeighbourhoodSides
+	// NeighbourhoodVonNeumann reads the 4 adjacent cells clockwise starting from the cell ahead
+	NeighbourhoodVonNeumann
+	// NeighbourhoodMoore reads the 8 surrounding cells clockwise starting from the cell ahead
+	NeighbourhoodMoore
+	// NeighbourhoodInvalid is an invalid neighbourhood
+	NeighbourhoodInvalid
+)
+
+var neighbourhoodNames = [NeighbourhoodInvalid]string{
+	"ahead",
+	"sides",
+	"vonneumann",
+	"moore",
+}
+
+// String returns the name of the neighbourhood used in the rule format
+func (n Neighbourhood) String() string {
+	if n < 0 || n >= NeighbourhoodInvalid {
+		return "invalid"
+	}
+	return neighbourhoodNames[n]
+}
+
+// Size returns the number of cells in the neighbourhood
+func (n Neighbourhood) Size() int {
+	switch n {
+	case NeighbourhoodAhead:
+		return 1
+	case NeighbourhoodSides:
+		return 2
+	case NeighbourhoodVonNeumann:
+		return 4
+	case NeighbourhoodMoore:
+		return 8
+	default:
+		panic("Invalid neighbourhood provided")
+	}
+}
+
+// Points returns the points of the neighbourhood for an ant at p facing the given direction
+func (n Neighbourhood) Points(p Point, direction Direction) []Point {
+	ahead := p.Walk(direction)
+	left := p.Walk(direction.Turn(ActionTurnLeft))
+	right := p.Walk(direction.Turn(ActionTurnRight))
+	switch n {
+	case NeighbourhoodAhead:
+		return []Point{ahead}
+	case NeighbourhoodSides:
+		return []Point{left, right}
+	case NeighbourhoodVonNeumann:
+		behind := p.Walk(direction.Turn(ActionTurnRight).Turn(ActionTurnRight))
+		return []Point{ahead, right, behind, left}
+	case NeighbourhoodMoore:
+		back := direction.Turn(ActionTurnRight).Turn(ActionTurnRight)
+		behind := p.Walk(back)
+		return []Point{
+			ahead,
+			ahead.Walk(direction.Turn(ActionTurnRight)),
+			right,
+			behind.Walk(direction.Turn(ActionTurnRight)),
+			behind,
+			behind.Walk(direction.Turn(ActionTurnLeft)),
+			left,
+			ahead.Walk(direction.Turn(ActionTurnLeft)),
+		}
+	default:
+		panic("Invalid neighbourhood provided")
+	}
+}
+
+// sensingWildcard matches any colour in a SensingRule pattern
+const sensingWildcard = '*'
+
+// sensingDigits are used to write colour indexes in SensingRule patterns
+const sensingDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// SensingRule chooses the action from the colour of the current cell and the colours of its neighbours.
+// Each entry is a pattern with one digit for the current cell followed by one digit per neighbour,
+// in the order given by Neighbourhood.Points. Digits are base 36 step indexes and * matches any colour.
+// The first matching entry wins, if none matches the ant takes the action of the current step.
+// Cells that have never been visited or are out of the board read as colour 0
+type SensingRule struct {
+	Neighbourhood Neighbourhood
+	Entries       []SensingEntry
+
+	cache map[string]sensingResult
+}
+
+// SensingEntry is a pattern and the action taken when it matches
+type SensingEntry struct {
+	Pattern string
+	Action  Action
+}
+
+type sensingResult struct {
+	action Action
+	ok     bool
+}
+
+var ErrInvalidSensingRule = errors.New("Invalid sensing rule")
+
+// ParseSensingRule parses a neighbourhood name followed by pattern:action entries separated by spaces,
+// "ahead 0*:L 10:R 11:S"
+func ParseSensingRule(rule string) (*SensingRule, error) {
+	fields := strings.Fields(rule)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidSensingRule)
+	}
+	out := &SensingRule{
+		Neighbourhood: NeighbourhoodInvalid,
+	}
+	for i, name := range neighbourhoodNames {
+		if strings.EqualFold(fields[0], name) {
+			out.Neighbourhood = Neighbourhood(i)
+		}
+	}
+	if out.Neighbourhood == NeighbourhoodInvalid {
+		return nil, fmt.Errorf("%w: unknown neighbourhood %q", ErrInvalidSensingRule, fields[0])
+	}
+	for _, field := range fields[1:] {
+		parts := strings.Split(field, ":")
+		if len(parts) != 2 || len(parts[1]) != 1 {
+			return nil, fmt.Errorf("%w: entry %q", ErrInvalidSensingRule, field)
+		}
+		action, err := parseAction(parts[1][0])
+		if err != nil {
+			return nil, err
+		}
+		out.Entries = append(out.Entries, SensingEntry{
+			Pattern: strings.ToLower(parts[0]),
+			Action:  action,
+		})
+	}
+	return out, out.Validate()
+}
+
+// Validate checks that every pattern has the right length and valid digits
+func (rule *SensingRule) Validate() error {
+	if rule.Neighbourhood < 0 || rule.Neighbourhood >= NeighbourhoodInvalid {
+		return fmt.Errorf("%w: invalid neighbourhood", ErrInvalidSensingRule)
+	}
+	size := rule.Neighbourhood.Size() + 1
+	for _, entry := range rule.Entries {
+		if len(entry.Pattern) != size {
+			return fmt.Errorf("%w: pattern %q must have %d digits", ErrInvalidSensingRule, entry.Pattern, size)
+		}
+		for _, c := range entry.Pattern {
+			if c != sensingWildcard && !strings.ContainsRune(sensingDigits, c) {
+				return fmt.Errorf("%w: invalid digit %q in pattern %q", ErrInvalidSensingRule, c, entry.Pattern)
+			}
+		}
+		if _, err := parseAction(byte(entry.Action)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns the rule in the format accepted by ParseSensingRule
+func (rule *SensingRule) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(rule.Neighbourhood.String())
+	for _, entry := range rule.Entries {
+		builder.WriteRune(' ')
+		builder.WriteString(entry.Pattern)
+		builder.WriteRune(':')
+		builder.WriteRune(rune(entry.Action))
+	}
+	return builder.String()
+}
+
+// Action returns the action for the ant state, ok is false if no entry matches
+func (rule *SensingRule) Action(ant *Ant) (action Action, ok bool) {
+	points := rule.Neighbourhood.Points(ant.Position.Point, ant.Direction)
+	key := make([]byte, 0, len(points)+1)
+	key = append(key, colourDigit(ant.Position.Step.Index))
+	for _, p := range points {
+		index := 0
+		cell, ok := ant.Board.Cell(p)
+		if ok {
+			index = cell.Step.Index
+		}
+		key = append(key, colourDigit(index))
+	}
+
+	if result, found := rule.cache[string(key)]; found {
+		return result.action, result.ok
+	}
+	result := sensingResult{}
+	for _, entry := range rule.Entries {
+		if patternMatches(entry.Pattern, key) {
+			result = sensingResult{
+				action: entry.Action,
+				ok:     true,
+			}
+			break
+		}
+	}
+	if rule.cache == nil {
+		rule.cache = map[string]sensingResult{}
+	}
+	rule.cache[string(key)] = result
+	return result.action, result.ok
+}
+
+func colourDigit(index int) byte {
+	if index >= len(sensingDigits) {
+		panic("sensing rules support up to " + strconv.Itoa(len(sensingDigits)) + " colours")
+	}
+	return sensingDigits[index]
+}
+
+func patternMatches(pattern string, key []byte) bool {
+	for i := range key {
+		if pattern[i] != sensingWildcard && pattern[i] != key[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// SetSensingRule makes the ant choose its actions with the given rule, nil removes it.
+// Cells keep cycling through the ant steps, which can not have more colours than the rule supports
+// in any phase of the schedule. The ant keeps a copy of the rule,
+// so the same rule can be set to ants that run in different goroutines
+func (ant *Ant) SetSensingRule(rule *SensingRule) error {
+	if rule == nil {
+		ant.sensing = nil
+		return nil
+	}
+	err := rule.Validate()
+	if err != nil {
+		return err
+	}
+	if ant.turmite != nil {
+		return fmt.Errorf("%w: a turmite can not follow a sensing rule", ErrInvalidSensingRule)
+	}
+	colours := len(ant.steps)
+	if ant.schedule != nil && ant.schedule.schedule.MaxColors() > colours {
+		colours = ant.schedule.schedule.MaxColors()
+	}
+	if colours > len(sensingDigits) {
+		return fmt.Errorf("%w: sensing rules support up to %d colours", ErrInvalidSensingRule, len(sensingDigits))
+	}
+	sensing := &SensingRule{
+		Neighbourhood: rule.Neighbourhood,
+		Entries:       append([]SensingEntry{}, rule.Entries...),
+	}
+	ant.sensing = sensing
+	return nil
+}
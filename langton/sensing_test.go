@@ -0,0 +1,137 @@
+package langton
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNeighbourhood_Points(t *testing.T) {
+	tests := []struct {
+		name          string
+		neighbourhood Neighbourhood
+		direction     Direction
+		want          []Point
+	}{
+		{
+			name:          "ahead facing top",
+			neighbourhood: NeighbourhoodAhead,
+			direction:     DirectionTop,
+			want:          []Point{{0, 1}},
+		},
+		{
+			name:          "sides facing right",
+			neighbourhood: NeighbourhoodSides,
+			direction:     DirectionRight,
+			want:          []Point{{0, 1}, {0, -1}},
+		},
+		{
+			name:          "von neumann facing top",
+			neighbourhood: NeighbourhoodVonNeumann,
+			direction:     DirectionTop,
+			want:          []Point{{0, 1}, {1, 0}, {0, -1}, {-1, 0}},
+		},
+		{
+			name:          "moore facing top",
+			neighbourhood: NeighbourhoodMoore,
+			direction:     DirectionTop,
+			want:          []Point{{0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.neighbourhood.Points(Point{}, tt.direction); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Neighbourhood.Points() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSensingRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "ahead", rule: "ahead 0*:L 10:R 11:S"},
+		{name: "moore", rule: "moore 1********:R"},
+		{name: "unknown neighbourhood", rule: "behind 00:L", wantErr: true},
+		{name: "wrong length", rule: "sides 00:L", wantErr: true},
+		{name: "wrong action", rule: "ahead 00:X", wantErr: true},
+		{name: "wrong digit", rule: "ahead 0#:L", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSensingRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSensingRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.rule {
+				t.Errorf("SensingRule.String() = %s, want %s", got, tt.rule)
+			}
+		})
+	}
+}
+
+func TestAnt_SetSensingRule(t *testing.T) {
+	// A rule that ignores the neighbours must behave as the plain rule
+	rule, err := ParseSensingRule("moore 0********:L 1********:R")
+	if err != nil {
+		t.Fatalf("ParseSensingRule() error = %v", err)
+	}
+	sensing := NewAntFromString(NewBoard(10), "RL")
+	err = sensing.SetSensingRule(rule)
+	if err != nil {
+		t.Fatalf("Ant.SetSensingRule() error = %v", err)
+	}
+	plain := NewAntFromString(NewBoard(10), "LR")
+	for {
+		_, sensingErr := sensing.Next()
+		_, plainErr := plain.Next()
+		if (sensingErr != nil) != (plainErr != nil) {
+			t.Fatalf("sensing ant error = %v, plain ant error %v", sensingErr, plainErr)
+		}
+		if sensingErr != nil {
+			break
+		}
+	}
+	if sensing.Position.Point != plain.Position.Point || sensing.TotalSteps() != plain.TotalSteps() {
+		t.Errorf("sensing ant at %s after %d steps, want %s after %d steps",
+			sensing.Position.Point, sensing.TotalSteps(), plain.Position.Point, plain.TotalSteps())
+	}
+
+	// Straight when the cell ahead is visited, the ant must reach the border without failing
+	rule, err = ParseSensingRule("ahead *1:S")
+	if err != nil {
+		t.Fatalf("ParseSensingRule() error = %v", err)
+	}
+	ant := NewAntFromString(NewBoard(3), "LR")
+	ant.SetSensingRule(rule)
+	_, err = ant.NextN(1000)
+	if err == nil {
+		t.Errorf("Ant.NextN() expected out of bounds error")
+	}
+}
+
+func TestAnt_SetSensingRule_Shared(t *testing.T) {
+	rule, err := ParseSensingRule("vonneumann 0****:L 1****:R")
+	if err != nil {
+		t.Fatalf("ParseSensingRule() error = %v", err)
+	}
+	done := make(chan string)
+	for i := 0; i < 2; i++ {
+		ant := NewAntFromString(NewBoard(30), "LR")
+		if err := ant.SetSensingRule(rule); err != nil {
+			t.Fatalf("Ant.SetSensingRule() error = %v", err)
+		}
+		go func() {
+			ant.NextN(2000)
+			done <- ant.String()
+		}()
+	}
+	if a, b := <-done, <-done; a != b {
+		t.Errorf("ants with the same rule walk different boards")
+	}
+	if rule.cache != nil {
+		t.Errorf("Ant.SetSensingRule() ants fill the cache of the shared rule")
+	}
+}
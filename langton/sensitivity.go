@@ -0,0 +1,199 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// FlipColour is the Perturbation index that changes the cell to the next colour of the rule
+const FlipColour = -1
+
+var ErrInvalidPerturbation = errors.New("Invalid perturbation")
+
+// Perturbation is a change of a single cell applied to a copy of an ant
+type Perturbation struct {
+	// Step is the number of steps after the start of the analysis when the cell is changed
+	Step int64
+	// Offset is the position of the cell relative to the ant when the change is applied, zero is the cell under the ant
+	Offset Point
+	// Index is the step index written in the cell, FlipColour writes the next one
+	Index int
+}
+
+// apply changes the cell of the ant
+func (perturbation Perturbation) apply(ant *Ant) error {
+	p := Point{
+		X: ant.Position.X + perturbation.Offset.X,
+		Y: ant.Position.Y + perturbation.Offset.Y,
+	}
+	cell, _, err := ant.ensureCellAt(p)
+	if err != nil {
+		return err
+	}
+	index := perturbation.Index
+	if index == FlipColour {
+		index = (cell.Step.Index + 1) % len(ant.steps)
+	}
+	if index < 0 || index >= len(ant.steps) {
+		return fmt.Errorf("%w: step index %d out of the rule", ErrInvalidPerturbation, index)
+	}
+	cell.Step = ant.steps[index]
+	return ant.setCell(cell)
+}
+
+// DivergenceSample is how far apart the unchanged and the perturbed ants are at a step
+type DivergenceSample struct {
+	Step int64
+	// Distance is the euclidean distance between the positions of both ants
+	Distance float64
+	// Cells is the number of cells with a different colour in both boards
+	Cells int64
+}
+
+// Sensitivity is the divergence over time between an ant and a copy with a perturbation
+type Sensitivity struct {
+	Perturbation Perturbation
+	Samples      []DivergenceSample
+	// Lyapunov is the growth rate per step of log(1+Cells) after the perturbation, fitted by least squares.
+	// It is close to 0 or negative for rules that absorb the change and positive for rules where it spreads
+	Lyapunov float64
+	// Healed is true if both ants ended in the same state
+	Healed bool
+}
+
+// divergence keeps the set of cells that differ in both boards, it is notified of every cell written by either ant
+type divergence struct {
+	a, b  *Ant
+	cells map[Point]struct{}
+}
+
+func (divergence *divergence) check(p Point) {
+	a, _ := divergence.a.Board.Cell(p)
+	b, _ := divergence.b.Board.Cell(p)
+	if sameStep(a.Step, b.Step) {
+		delete(divergence.cells, p)
+		return
+	}
+	divergence.cells[p] = struct{}{}
+}
+
+func (divergence *divergence) sample(step int64) DivergenceSample {
+	a, b := divergence.a.Position.Point, divergence.b.Position.Point
+	return DivergenceSample{
+		Step:     step,
+		Distance: math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y)),
+		Cells:    int64(len(divergence.cells)),
+	}
+}
+
+// MeasureSensitivity runs two copies of the ant for the given steps, one unchanged and the other with the perturbation,
+// and samples their divergence every interval steps. The ant itself is not modified.
+// If one of the copies gets stuck, the samples taken so far are fitted and the error is returned.
+// It fails with ErrOutOfBounds and a nil result if the ant is stuck or the perturbed cell is out of the board
+func MeasureSensitivity(ant *Ant, perturbation Perturbation, steps int64, interval int64) (*Sensitivity, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: %d steps", ErrInvalidInterval, interval)
+	}
+	if ant.Stuck() {
+		return nil, fmt.Errorf("%w: the ant is stuck", ErrOutOfBounds)
+	}
+	if perturbation.Step < 0 || perturbation.Step > steps {
+		return nil, fmt.Errorf("%w: step %d out of the run", ErrInvalidPerturbation, perturbation.Step)
+	}
+	reference := ant.Clone()
+	perturbed := ant.Clone()
+	divergence := &divergence{
+		a:     reference,
+		b:     perturbed,
+		cells: map[Point]struct{}{},
+	}
+	reference.changed = divergence.check
+	perturbed.changed = divergence.check
+
+	result := &Sensitivity{
+		Perturbation: perturbation,
+	}
+	var err error
+	for done := int64(0); ; done++ {
+		if done == perturbation.Step {
+			err = perturbation.apply(perturbed)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if done%interval == 0 || done == steps {
+			result.Samples = append(result.Samples, divergence.sample(done))
+		}
+		if done == steps {
+			break
+		}
+		_, err = reference.Next()
+		if err == nil {
+			_, err = perturbed.Next()
+		}
+		if err != nil {
+			break
+		}
+	}
+	result.fit()
+	result.Healed = len(divergence.cells) == 0 &&
+		reference.Position.Point == perturbed.Position.Point &&
+		reference.Direction == perturbed.Direction &&
+		reference.TurmiteState() == perturbed.TurmiteState()
+	return result, err
+}
+
+// fit estimates the Lyapunov number from the samples taken after the perturbation
+func (sensitivity *Sensitivity) fit() {
+	xs, ys := []float64{}, []float64{}
+	for _, sample := range sensitivity.Samples {
+		if sample.Step < sensitivity.Perturbation.Step {
+			continue
+		}
+		xs = append(xs, float64(sample.Step))
+		ys = append(ys, math.Log1p(float64(sample.Cells)))
+	}
+	sensitivity.Lyapunov = slope(xs, ys)
+}
+
+// RuleSensitivity returns the mean Lyapunov number of the ant over several runs, each one flipping a random cell
+// next to the ant at the start. The random cells are drawn from a source with the given seed.
+// Runs that reach the border of the board are measured up to that step, and trials whose cell is out of the board
+// are skipped. It fails with ErrOutOfBounds if no trial could be measured, as it happens to stuck ants
+func RuleSensitivity(ant *Ant, trials int, steps int64, seed int64) (float64, error) {
+	random := NewRandom(seed)
+	interval := steps / 100
+	if interval == 0 {
+		interval = 1
+	}
+	total := 0.0
+	measured := 0
+	for i := 0; i < trials; i++ {
+		perturbation := Perturbation{
+			Offset: Point{
+				X: int64(random.Intn(3)) - 1,
+				Y: int64(random.Intn(3)) - 1,
+			},
+			Index: FlipColour,
+		}
+		result, err := MeasureSensitivity(ant, perturbation, steps, interval)
+		// the run is fitted up to the border of the board
+		if err != nil && !errors.Is(err, ErrOutOfBounds) {
+			return 0, err
+		}
+		// the ant is stuck or the flipped cell is out of the board
+		if result == nil {
+			continue
+		}
+		total += result.Lyapunov
+		measured++
+	}
+	if trials == 0 {
+		return 0, nil
+	}
+	if measured == 0 {
+		return 0, fmt.Errorf("%w: every perturbed cell is out of the board", ErrOutOfBounds)
+	}
+	return total / float64(measured), nil
+}
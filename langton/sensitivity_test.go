@@ -0,0 +1,97 @@
+package langton
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnt_Clone(t *testing.T) {
+	ant := NewAntFromString(NewBoard(50), "[L0.7R]RLR")
+	ant.Seed(3)
+	ant.NextN(500)
+	clone := ant.Clone()
+	if !clone.Equal(ant) {
+		t.Fatalf("Clone() differs in %v", clone.Diff(ant))
+	}
+
+	clone.NextN(500)
+	if ant.TotalSteps() != 500 {
+		t.Errorf("Clone() moved the original ant to step %d", ant.TotalSteps())
+	}
+	ant.NextN(500)
+	if !clone.Equal(ant) {
+		t.Errorf("Clone() continues differently, differs in %v", clone.Diff(ant))
+	}
+}
+
+func TestMeasureSensitivity(t *testing.T) {
+	ant := NewAntFromString(NewBoard(100), "RLR")
+	ant.NextN(1000)
+
+	// writing the colour the cell already has changes nothing
+	cell, _ := ant.CellAt(ant.Position.Point)
+	same, err := MeasureSensitivity(ant, Perturbation{Index: cell.Step.Index}, 1000, 100)
+	if err != nil {
+		t.Fatalf("MeasureSensitivity() error = %v", err)
+	}
+	if !same.Healed || same.Lyapunov != 0 {
+		t.Errorf("MeasureSensitivity() without change Healed = %v, Lyapunov = %v, want true, 0", same.Healed, same.Lyapunov)
+	}
+
+	flip, err := MeasureSensitivity(ant, Perturbation{Step: 100, Index: FlipColour}, 5000, 100)
+	if err != nil {
+		t.Fatalf("MeasureSensitivity() error = %v", err)
+	}
+	if ant.TotalSteps() != 1000 {
+		t.Errorf("MeasureSensitivity() moved the ant to step %d", ant.TotalSteps())
+	}
+	if len(flip.Samples) != 51 {
+		t.Fatalf("MeasureSensitivity() took %d samples, want 51", len(flip.Samples))
+	}
+	if before, at := flip.Samples[0], flip.Samples[1]; before.Cells != 0 || at.Cells != 1 {
+		t.Errorf("MeasureSensitivity() cells = %d before and %d at the perturbation, want 0 and 1", before.Cells, at.Cells)
+	}
+	if flip.Healed || flip.Lyapunov <= 0 {
+		t.Errorf("MeasureSensitivity() Healed = %v, Lyapunov = %v, want a divergence", flip.Healed, flip.Lyapunov)
+	}
+
+	_, err = MeasureSensitivity(ant, Perturbation{Index: 3}, 100, 10)
+	if !errors.Is(err, ErrInvalidPerturbation) {
+		t.Errorf("MeasureSensitivity() error = %v, want %v", err, ErrInvalidPerturbation)
+	}
+	_, err = MeasureSensitivity(ant, Perturbation{}, 100, 0)
+	if !errors.Is(err, ErrInvalidInterval) {
+		t.Errorf("MeasureSensitivity() error = %v, want %v", err, ErrInvalidInterval)
+	}
+}
+
+func TestRuleSensitivity_Edge(t *testing.T) {
+	ant := NewAntFromString(NewBoard(3), "LR")
+	for {
+		_, err := ant.Next()
+		if err != nil {
+			break
+		}
+	}
+	for seed := int64(0); seed < 10; seed++ {
+		_, err := RuleSensitivity(ant, 5, 100, seed)
+		if !errors.Is(err, ErrOutOfBounds) {
+			t.Errorf("RuleSensitivity() of a stuck ant error = %v, want %v", err, ErrOutOfBounds)
+		}
+	}
+
+	// the ant is one step away from the edge, the trials with cells out of the board are skipped
+	ant = NewAntFromString(NewBoard(3), "LR")
+	ant.Position.Point = Point{X: 3, Y: 0}
+	ant.Direction = DirectionLeft
+	for seed := int64(0); seed < 10; seed++ {
+		_, err := RuleSensitivity(ant, 5, 100, seed)
+		if err != nil && !errors.Is(err, ErrOutOfBounds) {
+			t.Errorf("RuleSensitivity() error = %v, want nil or %v", err, ErrOutOfBounds)
+		}
+	}
+	result, err := MeasureSensitivity(ant, Perturbation{Offset: Point{X: 1}, Index: FlipColour}, 100, 10)
+	if result != nil || !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("MeasureSensitivity() = %v, %v, want nil, %v", result, err, ErrOutOfBounds)
+	}
+}
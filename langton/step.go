@@ -1,6 +1,11 @@
 package langton
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Steps []Step
 
@@ -8,36 +13,128 @@ type Step struct {
 	Index  int
 	Action Action
 
+	// Alternative is taken instead of Action with probability 1-Probability.
+	// Steps without Alternative always take Action
+	Alternative Action
+	Probability float64
+
 	nextIndex     int
 	previousIndex int
 }
 
 func (step Step) String() string {
+	if step.Stochastic() {
+		return fmt.Sprintf(
+			"%d: %s %g, else %s",
+			step.Index,
+			step.Action,
+			step.Probability,
+			step.Alternative,
+		)
+	}
 	return fmt.Sprintf(
 		"%d: %s",
 		step.Index,
 		step.Action,
 	)
 }
+
+// Stochastic returns true if the step has an Alternative action
+func (step Step) Stochastic() bool {
+	return step.Alternative != ActionNone
+}
+
+// String returns the sequence in the format accepted by ParseSteps
+func (steps Steps) String() string {
+	builder := strings.Builder{}
+	for i := range steps {
+		if steps[i].Stochastic() {
+			builder.WriteRune('[')
+			builder.WriteRune(rune(steps[i].Action))
+			builder.WriteString(strconv.FormatFloat(steps[i].Probability, 'g', -1, 64))
+			builder.WriteRune(rune(steps[i].Alternative))
+			builder.WriteRune(']')
+			continue
+		}
+		builder.WriteRune(rune(steps[i].Action))
+	}
+	return builder.String()
+}
+
+// StepsFromString is like ParseSteps but never fails,
+// if the sequence is invalid each character is a step and the unknown ones take no action
 func StepsFromString(steps string) Steps {
-	out := make(Steps, len(steps), len(steps))
-	for i, c := range steps {
-		switch c {
-		case rune(ActionTurnLeft):
-			out[i] = Step{
-				Action: ActionTurnLeft,
-			}
-		case rune(ActionTurnRight):
-			out[i] = Step{
-				Action: ActionTurnRight,
-			}
-		case rune(ActionStraight):
-			out[i] = Step{
-				Action: ActionStraight,
+	out, err := ParseSteps(steps)
+	if err == nil {
+		return out
+	}
+	out = make(Steps, len(steps))
+	for i := 0; i < len(steps); i++ {
+		out[i].Action, _ = parseAction(steps[i])
+	}
+	return out
+}
+
+var ErrInvalidSteps = errors.New("Invalid steps")
+
+// ParseSteps parses a sequence of L, R, S and U characters.
+// A stochastic step is written between brackets as action, probability and alternative,
+// "[L0.9R]" turns left with probability 0.9, otherwise right
+func ParseSteps(steps string) (Steps, error) {
+	out := make(Steps, 0, len(steps))
+	for i := 0; i < len(steps); i++ {
+		c := steps[i]
+		if c != '[' {
+			action, err := parseAction(c)
+			if err != nil {
+				return nil, err
 			}
+			out = append(out, Step{
+				Action: action,
+			})
+			continue
+		}
+
+		end := strings.IndexByte(steps[i:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unclosed bracket at %d", ErrInvalidSteps, i)
+		}
+		group := steps[i+1 : i+end]
+		if len(group) < 3 {
+			return nil, fmt.Errorf("%w: stochastic step %q is too short", ErrInvalidSteps, group)
+		}
+		action, err := parseAction(group[0])
+		if err != nil {
+			return nil, err
 		}
+		alternative, err := parseAction(group[len(group)-1])
+		if err != nil {
+			return nil, err
+		}
+		probability, err := strconv.ParseFloat(group[1:len(group)-1], 64)
+		if err != nil || probability < 0 || probability > 1 {
+			return nil, fmt.Errorf("%w: invalid probability in %q", ErrInvalidSteps, group)
+		}
+		out = append(out, Step{
+			Action:      action,
+			Alternative: alternative,
+			Probability: probability,
+		})
+		i += end
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: empty sequence", ErrInvalidSteps)
+	}
+	return out, nil
+}
+
+func parseAction(c byte) (Action, error) {
+	switch Action(c) {
+	case ActionTurnLeft, ActionTurnRight, ActionStraight, ActionUTurn:
+		return Action(c), nil
+	default:
+		return ActionNone, fmt.Errorf("%w: unknown action %q", ErrInvalidSteps, c)
 	}
-	return out
 }
 
 func (steps Steps) Numerate() {
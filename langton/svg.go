@@ -0,0 +1,299 @@
+package langton
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidSVG = errors.New("Invalid SVG")
+
+// DefaultSVGCellSize is the side in pixels of the cells of an SVG with CellSize 0
+const DefaultSVGCellSize = 10
+
+// SVG writes the board as a vector image. The cells of the same colour are merged in a single path
+// that follows the outline of each area, so the file grows with the length of the borders instead of
+// the number of cells. As in ToImage, the bottom left corner of the World is the top left corner of the image
+type SVG struct {
+	// World is the area drawn, the visited area if its Size is 0
+	World Dimensions
+	// CellSize is the side of a cell in pixels, DefaultSVGCellSize if 0
+	CellSize float64
+	// Marker draws the ant as a black dot with a red line where it is facing
+	Marker bool
+	// Grid draws the lines between cells, a line per row and column of the World
+	Grid bool
+	// Legend draws under the board the colour of each step of the rule
+	Legend bool
+}
+
+func (svg *SVG) cellSize() float64 {
+	if svg.CellSize <= 0 {
+		return DefaultSVGCellSize
+	}
+	return svg.CellSize
+}
+
+// Size of the legend items in pixels
+const (
+	legendBox    = 16
+	legendRow    = 24
+	legendChar   = 9
+	legendMargin = 12
+)
+
+// Write writes the SVG of the ant with the palette, the palette index 0 is the background and it is not drawn
+func (svg *SVG) Write(w io.Writer, ant *Ant, palette color.Palette) error {
+	world := svg.World
+	if world.Size == 0 {
+		world = ant.Visited()
+	}
+	if world.Width() <= 0 || world.Height() <= 0 {
+		return fmt.Errorf("%w: empty world %s", ErrInvalidSVG, &world)
+	}
+	if len(palette) < len(ant.steps)+1 {
+		return fmt.Errorf("%w: %d colours for %d steps", ErrInvalidSVG, len(palette)-1, len(ant.steps))
+	}
+
+	grid := newColourGrid(ant, world)
+	size := svg.cellSize()
+	width := float64(world.Width()) * size
+	height := float64(world.Height()) * size
+	labels, rows := svg.legend(ant, width)
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g">`+"\n",
+		width, height+float64(rows*legendRow), width, height+float64(rows*legendRow))
+	fmt.Fprintf(writer, `<g transform="scale(%g)" shape-rendering="crispEdges">`+"\n", size)
+	for colour, edges := range grid.edges() {
+		if len(edges) == 0 {
+			continue
+		}
+		fill, ok := svgFill(palette[colour])
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(writer, `<path %s fill-rule="evenodd" d="%s"/>`+"\n", fill, svgPath(trace(edges)))
+	}
+	if svg.Grid {
+		svg.writeGrid(writer, world)
+	}
+	if svg.Marker && world.Contains(ant.Position.Point) {
+		svg.writeMarker(writer, ant, world)
+	}
+	writer.WriteString("</g>\n")
+	for i, label := range labels {
+		fill, _ := svgFill(palette[i+1])
+		fmt.Fprintf(writer, `<rect x="%g" y="%g" width="%d" height="%d" %s stroke="#000"/>`+"\n",
+			label.x, height+label.y, legendBox, legendBox, fill)
+		fmt.Fprintf(writer, `<text x="%g" y="%g" font-family="monospace" font-size="14">%s</text>`+"\n",
+			label.x+legendBox+4, height+label.y+legendBox-3, label.text)
+	}
+	writer.WriteString("</svg>\n")
+	return writer.Flush()
+}
+
+// legendLabel is a step of the legend placed at x, y under the board
+type legendLabel struct {
+	text string
+	x, y float64
+}
+
+// legend places the labels of the steps in rows as wide as the board, it returns the number of rows used
+func (svg *SVG) legend(ant *Ant, width float64) ([]legendLabel, int) {
+	if !svg.Legend {
+		return nil, 0
+	}
+	labels := make([]legendLabel, len(ant.steps))
+	x, row := 0.0, 0
+	for i := range ant.steps {
+		text := strconv.Itoa(i) + " " + Steps(ant.steps[i:i+1]).String()
+		itemWidth := float64(legendBox + 4 + legendChar*len(text) + legendMargin)
+		if x > 0 && x+itemWidth > width {
+			x = 0
+			row++
+		}
+		labels[i] = legendLabel{
+			text: text,
+			x:    x + legendMargin/2,
+			y:    float64(row*legendRow + (legendRow-legendBox)/2),
+		}
+		x += itemWidth
+	}
+	return labels, row + 1
+}
+
+func (svg *SVG) writeGrid(writer *bufio.Writer, world Dimensions) {
+	builder := strings.Builder{}
+	for x := int64(0); x <= world.Width(); x++ {
+		fmt.Fprintf(&builder, "M%d 0V%d", x, world.Height())
+	}
+	for y := int64(0); y <= world.Height(); y++ {
+		fmt.Fprintf(&builder, "M0 %dH%d", y, world.Width())
+	}
+	fmt.Fprintf(writer, `<path d="%s" fill="none" stroke="#000" stroke-opacity="0.25" stroke-width="1" vector-effect="non-scaling-stroke"/>`+"\n", builder.String())
+}
+
+func (svg *SVG) writeMarker(writer *bufio.Writer, ant *Ant, world Dimensions) {
+	x := float64(ant.Position.X-world.BottomLeft.X) + 0.5
+	y := float64(ant.Position.Y-world.BottomLeft.Y) + 0.5
+	facing := ant.Position.Point.Walk(ant.Direction)
+	dx := float64(facing.X-ant.Position.X) * 0.4
+	dy := float64(facing.Y-ant.Position.Y) * 0.4
+	fmt.Fprintf(writer, `<circle cx="%g" cy="%g" r="0.4" fill="#000"/>`+"\n", x, y)
+	fmt.Fprintf(writer, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="#f00" stroke-width="0.1"/>`+"\n", x, y, x+dx, y+dy)
+}
+
+// svgFill returns the fill attributes of the colour, ok is false for transparent colours
+func svgFill(c color.Color) (string, bool) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0 {
+		return "", false
+	}
+	fill := fmt.Sprintf(`fill="#%02x%02x%02x"`, n.R, n.G, n.B)
+	if n.A < 255 {
+		fill += fmt.Sprintf(` fill-opacity="%.3f"`, float64(n.A)/255)
+	}
+	return fill, true
+}
+
+// colourGrid holds the palette index of every cell of an area, 0 for the cells that have not been visited.
+// The area starts at offset from the corner of the drawn world
+type colourGrid struct {
+	width, height int64
+	offset        Point
+	colours       []int32
+	count         int
+}
+
+// newColourGrid only covers the visited part of the world, the rest is background and has no edges
+func newColourGrid(ant *Ant, world Dimensions) *colourGrid {
+	grid := &colourGrid{
+		count: len(ant.steps) + 1,
+	}
+	area, ok := world.Intersect(ant.Visited())
+	if !ok {
+		return grid
+	}
+	grid.width = area.Width()
+	grid.height = area.Height()
+	grid.offset = Point{X: area.BottomLeft.X - world.BottomLeft.X, Y: area.BottomLeft.Y - world.BottomLeft.Y}
+	grid.colours = make([]int32, area.Width()*area.Height())
+	ant.Region(area, func(cell Cell) bool {
+		grid.colours[(cell.Y-area.BottomLeft.Y)*grid.width+cell.X-area.BottomLeft.X] = int32(cell.Step.Index + 1)
+		return true
+	})
+	return grid
+}
+
+// at returns the colour of the cell, -1 out of the grid
+func (grid *colourGrid) at(x, y int64) int32 {
+	if x < 0 || y < 0 || x >= grid.width || y >= grid.height {
+		return -1
+	}
+	return grid.colours[y*grid.width+x]
+}
+
+// edge is a side of a cell between two corners
+type edge struct {
+	from, to Point
+}
+
+// edges returns for each colour the sides of its cells that touch a cell of another colour, none for the background.
+// The sides of a cell go counter-clockwise, so the outline of an area is a closed chain of edges.
+// The corners are relative to the drawn world
+func (grid *colourGrid) edges() [][]edge {
+	out := make([][]edge, grid.count)
+	for y := int64(0); y < grid.height; y++ {
+		for x := int64(0); x < grid.width; x++ {
+			c := grid.at(x, y)
+			if c == 0 {
+				continue
+			}
+			left, bottom := x+grid.offset.X, y+grid.offset.Y
+			if grid.at(x, y-1) != c {
+				out[c] = append(out[c], edge{Point{X: left, Y: bottom}, Point{X: left + 1, Y: bottom}})
+			}
+			if grid.at(x+1, y) != c {
+				out[c] = append(out[c], edge{Point{X: left + 1, Y: bottom}, Point{X: left + 1, Y: bottom + 1}})
+			}
+			if grid.at(x, y+1) != c {
+				out[c] = append(out[c], edge{Point{X: left + 1, Y: bottom + 1}, Point{X: left, Y: bottom + 1}})
+			}
+			if grid.at(x-1, y) != c {
+				out[c] = append(out[c], edge{Point{X: left, Y: bottom + 1}, Point{X: left, Y: bottom}})
+			}
+		}
+	}
+	return out
+}
+
+// trace joins the edges in closed polygons and removes the corners in the middle of straight lines.
+// Every corner has as many edges leaving as arriving, so following any unused edge always closes the polygon
+func trace(edges []edge) [][]Point {
+	outgoing := make(map[Point][]int, len(edges))
+	for i, e := range edges {
+		outgoing[e.from] = append(outgoing[e.from], i)
+	}
+	used := make([]bool, len(edges))
+	polygons := [][]Point{}
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+		start := edges[i].from
+		polygon := []Point{start}
+		for current := i; ; {
+			used[current] = true
+			end := edges[current].to
+			if end == start {
+				break
+			}
+			polygon = append(polygon, end)
+			next := outgoing[end]
+			for used[next[0]] {
+				next = next[1:]
+			}
+			current = next[0]
+			outgoing[end] = next[1:]
+		}
+		polygons = append(polygons, simplify(polygon))
+	}
+	return polygons
+}
+
+// simplify removes the corners between two edges in the same line
+func simplify(polygon []Point) []Point {
+	out := make([]Point, 0, len(polygon))
+	for i, p := range polygon {
+		previous := polygon[(i+len(polygon)-1)%len(polygon)]
+		next := polygon[(i+1)%len(polygon)]
+		if (previous.X == p.X && p.X == next.X) || (previous.Y == p.Y && p.Y == next.Y) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// svgPath returns the path data of the polygons, consecutive corners always share an axis
+func svgPath(polygons [][]Point) string {
+	builder := strings.Builder{}
+	for _, polygon := range polygons {
+		for i, p := range polygon {
+			switch {
+			case i == 0:
+				fmt.Fprintf(&builder, "M%d %d", p.X, p.Y)
+			case p.Y == polygon[i-1].Y:
+				fmt.Fprintf(&builder, "H%d", p.X)
+			default:
+				fmt.Fprintf(&builder, "V%d", p.Y)
+			}
+		}
+		builder.WriteString("Z")
+	}
+	return builder.String()
+}
@@ -0,0 +1,160 @@
+package langton
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVGMergesCells(t *testing.T) {
+	steps := StepsFromString("LR")
+	steps.Numerate()
+	board := NewSparseBoard(NewBoard(10))
+	// a square of 3x3 cells with a hole of the other colour
+	for y := int64(0); y < 3; y++ {
+		for x := int64(0); x < 3; x++ {
+			board.SetCell(Cell{Point: Point{X: x, Y: y}, Step: steps[0]})
+		}
+	}
+	board.SetCell(Cell{Point: Point{X: 1, Y: 1}, Step: steps[1]})
+	ant := NewAntOnBoard(board, steps...)
+	palette := color.Palette{color.Alpha{}, color.RGBA{R: 255, A: 255}, color.NRGBA{B: 255, A: 128}}
+
+	buf := &bytes.Buffer{}
+	err := (&SVG{CellSize: 4}).Write(buf, ant, palette)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`width="12" height="12"`,
+		`<path fill="#ff0000" fill-rule="evenodd" d="M0 0H3V3H0ZM2 1H1V2H2Z"/>`,
+		`<path fill="#0000ff" fill-opacity="0.502" fill-rule="evenodd" d="M1 1H2V2H1Z"/>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Write() = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestSVGWorld(t *testing.T) {
+	steps := StepsFromString("LR")
+	steps.Numerate()
+	board := NewSparseBoard(NewDimensions(-1<<30, -1<<30, 1<<30, 1<<30))
+	board.SetCell(Cell{Point: Point{X: 0, Y: 0}, Step: steps[0]})
+	board.SetCell(Cell{Point: Point{X: 1, Y: 0}, Step: steps[1]})
+	ant := NewAntOnBoard(board, steps...)
+	palette := color.Palette{color.Alpha{}, color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}}
+
+	tests := []struct {
+		name  string
+		world Dimensions
+		want  []string
+	}{
+		{
+			name:  "around the visited cells",
+			world: NewDimensions(-2, -1, 5, 5),
+			want: []string{
+				`<path fill="#ff0000" fill-rule="evenodd" d="M2 1H3V2H2Z"/>`,
+				`<path fill="#0000ff" fill-rule="evenodd" d="M3 1H4V2H3Z"/>`,
+			},
+		},
+		{
+			name:  "cutting the visited cells",
+			world: NewDimensions(1, 0, 3, 2),
+			want: []string{
+				`<path fill="#0000ff" fill-rule="evenodd" d="M0 0H1V1H0Z"/>`,
+			},
+		},
+		{
+			// the grid only covers the visited cells, not the whole world
+			name:  "whole board",
+			world: board.Dimensions(),
+			want: []string{
+				`<path fill="#ff0000" fill-rule="evenodd" d="M1073741824 1073741824H1073741825V1073741825H1073741824Z"/>`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := (&SVG{World: tt.world}).Write(buf, ant, palette)
+			if err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			got := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Write() = %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSVGArea(t *testing.T) {
+	ant := NewAntFromString(NewBoard(100), "LRRRRRLLR")
+	ant.NextN(20000)
+	grid := newColourGrid(ant, ant.Visited())
+	histogram := ant.Histogram()
+	for colour, edges := range grid.edges() {
+		if colour == 0 {
+			continue
+		}
+		// outlines go counter-clockwise and holes clockwise, so the signed area is the number of cells
+		var area int64
+		for _, polygon := range trace(edges) {
+			for i, p := range polygon {
+				q := polygon[(i+1)%len(polygon)]
+				area += p.X*q.Y - q.X*p.Y
+			}
+		}
+		if area/2 != histogram[colour-1] {
+			t.Errorf("trace() area of colour %d = %d, want %d", colour, area/2, histogram[colour-1])
+		}
+	}
+}
+
+func TestSVGOptions(t *testing.T) {
+	ant := NewAntFromString(NewBoard(20), "LLRR")
+	ant.NextN(500)
+	palette := ToPalette(GradientPalette(len(ant.steps)))
+
+	buf := &bytes.Buffer{}
+	err := (&SVG{Marker: true, Grid: true, Legend: true}).Write(buf, ant, palette)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	elements := map[string]int{}
+	decoder := xml.NewDecoder(buf)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Write() is not valid XML, %v", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			elements[start.Name.Local]++
+		}
+	}
+	// a path per colour and the grid
+	if elements["path"] > len(ant.steps)+1 {
+		t.Errorf("Write() has %d paths, want at most %d", elements["path"], len(ant.steps)+1)
+	}
+	if elements["circle"] != 1 || elements["line"] != 1 {
+		t.Errorf("Write() elements = %v, want the ant marker", elements)
+	}
+	if elements["rect"] != len(ant.steps) || elements["text"] != len(ant.steps) {
+		t.Errorf("Write() elements = %v, want a legend item per step", elements)
+	}
+
+	err = (&SVG{}).Write(buf, ant, palette[:2])
+	if err == nil {
+		t.Errorf("Write() error = nil, want %v", ErrInvalidSVG)
+	}
+}
@@ -0,0 +1,130 @@
+package langton
+
+// Mirror returns the rule with left and right turns swapped.
+// The ant draws the same board reflected around the vertical line that goes through its starting cell
+func Mirror(steps Steps) Steps {
+	out := make(Steps, len(steps))
+	for i, step := range steps {
+		step.Action = mirrorAction(step.Action)
+		step.Alternative = mirrorAction(step.Alternative)
+		out[i] = step
+	}
+	out.Numerate()
+	return out
+}
+
+func mirrorAction(action Action) Action {
+	switch action {
+	case ActionTurnLeft:
+		return ActionTurnRight
+	case ActionTurnRight:
+		return ActionTurnLeft
+	default:
+		return action
+	}
+}
+
+// Complement returns the rule with every turn replaced by the opposite one, reading L as 0 and R as 1 it is the binary complement.
+// Straight steps have no opposite and are kept, so for the actions supported it is the same transform as Mirror
+func Complement(steps Steps) Steps {
+	return Mirror(steps)
+}
+
+// RotateColours returns the rule with the colour cycle shifted n positions, the step n becomes the first one.
+// Unvisited cells always start with the first step, so the rotated rule is not equivalent to the original one
+// unless it is made of repetitions of n steps
+func RotateColours(steps Steps, n int) Steps {
+	out := make(Steps, len(steps))
+	if len(steps) == 0 {
+		return out
+	}
+	n %= len(steps)
+	if n < 0 {
+		n += len(steps)
+	}
+	copy(out, steps[n:])
+	copy(out[len(steps)-n:], steps[:n])
+	out.Numerate()
+	return out
+}
+
+// Root returns the shortest rule that repeated gives the original one, "LRLR" returns "LR".
+// Both rules draw the same path, a cell with step index i in the original has index i % len(root) in the root
+func Root(steps Steps) Steps {
+	for period := 1; period < len(steps); period++ {
+		if len(steps)%period != 0 {
+			continue
+		}
+		repeated := true
+		for i := period; i < len(steps) && repeated; i++ {
+			repeated = sameAction(steps[i], steps[i-period])
+		}
+		if repeated {
+			return numerated(steps[:period])
+		}
+	}
+	return numerated(steps)
+}
+
+func sameAction(a, b Step) bool {
+	return a.Action == b.Action && a.Alternative == b.Alternative && a.Probability == b.Probability
+}
+
+// Relation states how the board of a rule is obtained from the board of another rule that is equivalent
+type Relation struct {
+	// Mirror is true if the board is reflected around the vertical line that goes through the starting cell
+	Mirror bool
+	// Colours is the length of the other rule, a cell with step index i has index i % Colours in the other board
+	Colours int
+}
+
+// Point returns the point of the other board that corresponds to p, start is the starting cell of the ants
+func (relation Relation) Point(p Point, start Point) Point {
+	if relation.Mirror {
+		p.X = 2*start.X - p.X
+	}
+	return p
+}
+
+// Direction returns the direction of the other ant that corresponds to d
+func (relation Relation) Direction(d Direction) Direction {
+	if relation.Mirror && (d == DirectionLeft || d == DirectionRight) {
+		return d.Turn(ActionTurnRight).Turn(ActionTurnRight)
+	}
+	return d
+}
+
+// Index returns the step index of the other board that corresponds to index
+func (relation Relation) Index(index int) int {
+	return index % relation.Colours
+}
+
+// Canonical returns the representative of the equivalence class of the rule and how the rule relates to it.
+// The canonical form is the Root of the rule or its Mirror, whichever is written first in alphabetical order.
+// Two rules are equivalent if they have the same canonical form
+func Canonical(steps Steps) (Steps, Relation) {
+	root := Root(steps)
+	mirror := Mirror(root)
+	if mirror.String() < root.String() {
+		return mirror, Relation{
+			Mirror:  true,
+			Colours: len(mirror),
+		}
+	}
+	return root, Relation{
+		Colours: len(root),
+	}
+}
+
+// IsCanonical returns true if the rule is its own canonical form
+func IsCanonical(steps Steps) bool {
+	canonical, _ := Canonical(steps)
+	return canonical.String() == steps.String()
+}
+
+// Equivalent returns true if both rules draw the same board up to a mirror image and a palette change
+func Equivalent(a, b Steps) bool {
+	canonicalA, _ := Canonical(a)
+	canonicalB, _ := Canonical(b)
+	return canonicalA.String() == canonicalB.String()
+}
@@ -0,0 +1,86 @@
+package langton
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		steps     string
+		canonical string
+		relation  Relation
+	}{
+		{"LR", "LR", Relation{Colours: 2}},
+		{"RL", "LR", Relation{Mirror: true, Colours: 2}},
+		{"RLRL", "LR", Relation{Mirror: true, Colours: 2}},
+		{"LLRR", "LLRR", Relation{Colours: 4}},
+		{"RRLLRRLL", "LLRR", Relation{Mirror: true, Colours: 4}},
+		{"SRL", "SLR", Relation{Mirror: true, Colours: 3}},
+		{"[R0.9L]", "[L0.9R]", Relation{Mirror: true, Colours: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.steps, func(t *testing.T) {
+			canonical, relation := Canonical(StepsFromString(tt.steps))
+			if canonical.String() != tt.canonical {
+				t.Errorf("Canonical() = %v, want %v", canonical, tt.canonical)
+			}
+			if relation != tt.relation {
+				t.Errorf("Canonical() relation = %+v, want %+v", relation, tt.relation)
+			}
+			if !Equivalent(StepsFromString(tt.steps), StepsFromString(tt.canonical)) {
+				t.Errorf("Equivalent() = false, want true")
+			}
+		})
+	}
+	if Equivalent(StepsFromString("LLR"), StepsFromString("LRL")) {
+		t.Errorf("Equivalent() = true, want false")
+	}
+}
+
+func TestRelation(t *testing.T) {
+	for _, rule := range []string{"RL", "LRLR", "RRLLRRLL", "LRRRRRLLR", "RLLLLLRRL"} {
+		t.Run(rule, func(t *testing.T) {
+			steps := StepsFromString(rule)
+			canonical, relation := Canonical(steps)
+			ant := NewAnt(NewBoard(40), steps...)
+			other := NewAnt(NewBoard(40), canonical...)
+			ant.NextN(2000)
+			other.NextN(2000)
+
+			dimensions := NewBoard(40)
+			start := dimensions.Center()
+			ant.Board.Each(func(cell Cell) bool {
+				p := relation.Point(cell.Point, start)
+				got, ok := other.Board.Cell(p)
+				if !ok || got.Step.Index != relation.Index(cell.Step.Index) {
+					t.Fatalf("cell %v = %v, want index %d at %v", cell.Point, got, relation.Index(cell.Step.Index), p)
+				}
+				return true
+			})
+			if got := relation.Point(ant.Position.Point, start); got != other.Position.Point {
+				t.Errorf("Relation.Point() = %v, want %v", got, other.Position.Point)
+			}
+			if got := relation.Direction(ant.Direction); got != other.Direction {
+				t.Errorf("Relation.Direction() = %v, want %v", got, other.Direction)
+			}
+		})
+	}
+}
+
+func TestRotateColours(t *testing.T) {
+	tests := []struct {
+		steps string
+		n     int
+		want  string
+	}{
+		{"LRS", 1, "RSL"},
+		{"LRS", -1, "SLR"},
+		{"LRS", 3, "LRS"},
+	}
+	for _, tt := range tests {
+		if got := RotateColours(StepsFromString(tt.steps), tt.n); got.String() != tt.want {
+			t.Errorf("RotateColours(%q, %d) = %v, want %v", tt.steps, tt.n, got, tt.want)
+		}
+	}
+	if got := Complement(StepsFromString("LRS")); got.String() != "RLS" {
+		t.Errorf("Complement() = %v, want %v", got, "RLS")
+	}
+}
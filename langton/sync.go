@@ -0,0 +1,203 @@
+package langton
+
+import "sync"
+
+// SyncAnt guards an Ant so it can be advanced by one goroutine while others render it.
+// Readers never touch the live board, they take a View that only copies the cells changed since its last update
+type SyncAnt struct {
+	mutex sync.Mutex
+	ant   *Ant
+	views []*View
+}
+
+// View is a private copy of a SyncAnt owned by a single reader
+type View struct {
+	source *SyncAnt
+	ant    *Ant
+	dirty  []Point
+	full   bool
+}
+
+// NewSyncAnt wraps the ant, it must not be used directly after this call
+func NewSyncAnt(ant *Ant) *SyncAnt {
+	s := &SyncAnt{
+		ant: ant,
+	}
+	ant.changed = s.markDirty
+	return s
+}
+
+// Next calls Ant.Next holding the lock and returns a copy of the new position
+func (s *SyncAnt) Next() (Cell, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cell, err := s.next()
+	return *cell, err
+}
+
+// NextN calls Ant.Next n times holding the lock and returns a copy of the new position
+func (s *SyncAnt) NextN(steps int) (Cell, error) {
+	if steps < 0 {
+		panic("steps must be >= 0")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cell := &s.ant.Position
+	var err error
+	for i := 0; i < steps; i++ {
+		cell, err = s.next()
+		if err != nil {
+			break
+		}
+	}
+	return *cell, err
+}
+
+// next performs a single step, the ant notifies the modified cells through markDirty
+func (s *SyncAnt) next() (*Cell, error) {
+	cell, err := s.ant.Next()
+	if cell == nil {
+		// The ant was already stuck, nothing changed
+		return &s.ant.Position, err
+	}
+	return cell, err
+}
+
+// markDirty marks a modified cell on every view
+func (s *SyncAnt) markDirty(p Point) {
+	for _, view := range s.views {
+		view.markDirty(p)
+	}
+}
+
+// Grow calls Ant.Grow holding the lock
+func (s *SyncAnt) Grow(dimensions Dimensions) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.ant.Grow(dimensions)
+	if err != nil {
+		return err
+	}
+	s.markFull()
+	return nil
+}
+
+// Crop calls Ant.Crop holding the lock
+func (s *SyncAnt) Crop(margin int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := s.ant.Crop(margin)
+	if err != nil {
+		return err
+	}
+	s.markFull()
+	return nil
+}
+
+// markFull forces a full copy on every view
+func (s *SyncAnt) markFull() {
+	for _, view := range s.views {
+		view.markFull()
+	}
+}
+
+// TotalSteps returns the total steps performed by the ant
+func (s *SyncAnt) TotalSteps() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.ant.TotalSteps()
+}
+
+// Dimensions returns the current board dimensions
+func (s *SyncAnt) Dimensions() Dimensions {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.ant.Dimensions()
+}
+
+// NewView creates a new View, the first call to Update copies the whole board
+func (s *SyncAnt) NewView() *View {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	view := &View{
+		source: s,
+		full:   true,
+	}
+	s.views = append(s.views, view)
+	return view
+}
+
+// Update brings the view up to date with the live ant and returns it.
+// The returned Ant is modified in place by the next call to Update, so it must only be used by the view owner
+func (view *View) Update() *Ant {
+	s := view.source
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if view.full {
+		board := s.ant.Board.Empty(s.ant.Dimensions())
+		copyBoard(board, s.ant.Board)
+		view.ant = &Ant{
+			Board: board,
+		}
+		view.full = false
+	} else {
+		for _, p := range view.dirty {
+			cell, ok := s.ant.Board.Cell(p)
+			if !ok {
+				cell = Cell{
+					Point: p,
+				}
+			}
+			view.ant.Board.SetCell(cell)
+		}
+	}
+	view.dirty = view.dirty[:0]
+
+	view.ant.Position = s.ant.Position
+	view.ant.Direction = s.ant.Direction
+	view.ant.steps = s.ant.steps
+	view.ant.totalSteps = s.ant.totalSteps
+	view.ant.stuck = s.ant.stuck
+	view.ant.visited = s.ant.visited
+	view.ant.histogram = append(view.ant.histogram[:0], s.ant.histogram...)
+	view.ant.boardHash = s.ant.boardHash
+	return view.ant
+}
+
+// Close stops tracking changes for the view
+func (view *View) Close() {
+	s := view.source
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.views {
+		if s.views[i] == view {
+			s.views = append(s.views[:i], s.views[i+1:]...)
+			break
+		}
+	}
+}
+
+// markDirty records a changed point, it falls back to a full copy if too many points have changed
+func (view *View) markDirty(p Point) {
+	if view.full {
+		return
+	}
+	dimensions := view.source.ant.Dimensions()
+	if int64(len(view.dirty)) >= dimensions.Size/4 {
+		view.markFull()
+		return
+	}
+	view.dirty = append(view.dirty, p)
+}
+
+func (view *View) markFull() {
+	view.full = true
+	view.dirty = nil
+}
@@ -0,0 +1,60 @@
+package langton
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncAnt_View(t *testing.T) {
+	ant := NewAntFromString(NewBoard(20), "RLLLLRRRLLL")
+	syncAnt := NewSyncAnt(ant)
+	view := syncAnt.NewView()
+	defer view.Close()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			syncAnt.NextN(100)
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		snapshot := view.Update()
+		_ = snapshot.String()
+	}
+	wg.Wait()
+
+	snapshot := view.Update()
+	if snapshot.String() != ant.String() {
+		t.Errorf("View.Update() = \n%s, want \n%s", snapshot, ant)
+	}
+	if snapshot.Position.Point != ant.Position.Point {
+		t.Errorf("View.Update() position = %s, want %s", snapshot.Position.Point, ant.Position.Point)
+	}
+	if snapshot.TotalSteps() != ant.TotalSteps() {
+		t.Errorf("View.Update() total steps = %d, want %d", snapshot.TotalSteps(), ant.TotalSteps())
+	}
+}
+
+func TestSyncAnt_Grow(t *testing.T) {
+	ant := NewAntFromString(NewBoard(2), "LR")
+	syncAnt := NewSyncAnt(ant)
+	view := syncAnt.NewView()
+	defer view.Close()
+
+	syncAnt.NextN(1000)
+	view.Update()
+
+	err := syncAnt.Grow(NewBoard(5))
+	if err != nil {
+		t.Fatalf("SyncAnt.Grow() error = %v", err)
+	}
+	syncAnt.NextN(10)
+
+	snapshot := view.Update()
+	if snapshot.String() != ant.String() {
+		t.Errorf("View.Update() = \n%s, want \n%s", snapshot, ant)
+	}
+}
@@ -9,67 +9,144 @@ import (
 	"golang.org/x/image/colornames"
 )
 
+// maxPalettedColours is the number of step colours that fit in an image.Paletted besides transparent, black and red
+const maxPalettedColours = 256 - 3
+
 // ToImage generates a image.Paletted with the current ant state.
-// The cell size is in pixels
+// The bottom left corner of the board is the first pixel and the cell size is in pixels
 // If the cell size is bigger than 5, the ant will be drawn as a black dot
+// Palettes with more colours than an image.Paletted can hold are quantised, use ToRGBA to keep every colour
 func ToImage(ant *Ant, palette color.Palette, cellSize int) *image.Paletted {
 
+	dimensions := ant.Dimensions()
 	r := image.Rect(
 		0,
 		0,
-		int(ant.Dimensions.width)*cellSize,
-		int(ant.Dimensions.height)*cellSize,
+		int(dimensions.width)*cellSize,
+		int(dimensions.height)*cellSize,
 	)
+	palette, colorIndex := quantise(palette)
 	palette = append(palette, colornames.Black, colornames.Red)
 	img := image.NewPaletted(r, palette)
-	for i := range ant.Cells {
-		if ant.Cells[i].Step.Action == ActionNone {
-			continue
-		}
-
+	ant.Board.Each(func(cell Cell) bool {
+		index := colorIndex(cell.Step.Index)
 		for sx := 0; sx < cellSize; sx++ {
 			for sy := 0; sy < cellSize; sy++ {
 				img.SetColorIndex(
-					int((ant.Cells[i].X+ant.Dimensions.width/2)*int64(cellSize)+int64(sx)),
-					int((ant.Cells[i].Y+ant.Dimensions.height/2)*int64(cellSize)+int64(sy)),
-					uint8(ant.Cells[i].Step.Index+1),
+					int((cell.X-dimensions.BottomLeft.X)*int64(cellSize)+int64(sx)),
+					int((cell.Y-dimensions.BottomLeft.Y)*int64(cellSize)+int64(sy)),
+					index,
 				)
 			}
 		}
-	}
+		return true
+	})
+
+	black := uint8(len(palette) - 2)
+	red := uint8(len(palette) - 1)
+	drawAntMarker(ant, cellSize, func(x, y int, direction bool) {
+		if direction {
+			img.SetColorIndex(x, y, red)
+			return
+		}
+		img.SetColorIndex(x, y, black)
+	})
+	return img
+}
 
-	black := len(palette) - 2
-	red := len(palette) - 1
-	if cellSize > 5 {
-		cell := ant.Position
+// ToRGBA generates a image.RGBA with the current ant state, the palette can have any number of colours.
+// It works as ToImage but it is bigger and slower to encode
+func ToRGBA(ant *Ant, palette color.Palette, cellSize int) *image.RGBA {
+	dimensions := ant.Dimensions()
+	r := image.Rect(
+		0,
+		0,
+		int(dimensions.width)*cellSize,
+		int(dimensions.height)*cellSize,
+	)
+	img := image.NewRGBA(r)
+	ant.Board.Each(func(cell Cell) bool {
+		c := palette[cell.Step.Index+1]
 		for sx := 0; sx < cellSize; sx++ {
 			for sy := 0; sy < cellSize; sy++ {
-				radius := cellSize / 2
-				if distance2From(sx, sy, radius, radius) <= (radius-1)*(radius-1) {
-					var color int
-					switch {
-					case ant.Direction == DirectionLeft && sx < radius && sy == radius:
-						color = red
-					case ant.Direction == DirectionRight && sx > radius && sy == radius:
-						color = red
-					case ant.Direction == DirectionTop && sx == radius && sy > radius:
-						color = red
-					case ant.Direction == DirectionDown && sx == radius && sy < radius:
-						color = red
-					default:
-						color = black
-					}
-
-					img.SetColorIndex(
-						int((cell.X+ant.Dimensions.width/2)*int64(cellSize)+int64(sx)),
-						int((cell.Y+ant.Dimensions.height/2)*int64(cellSize)+int64(sy)),
-						uint8(color),
-					)
+				img.Set(
+					int((cell.X-dimensions.BottomLeft.X)*int64(cellSize)+int64(sx)),
+					int((cell.Y-dimensions.BottomLeft.Y)*int64(cellSize)+int64(sy)),
+					c,
+				)
+			}
+		}
+		return true
+	})
+
+	drawAntMarker(ant, cellSize, func(x, y int, direction bool) {
+		if direction {
+			img.Set(x, y, colornames.Red)
+			return
+		}
+		img.Set(x, y, colornames.Black)
+	})
+	return img
+}
+
+// quantise reduces a palette built with ToPalette so it fits in an image.Paletted.
+// colorIndex returns the palette index of a step index, nearby steps share colour when the palette is reduced
+func quantise(palette color.Palette) (reduced color.Palette, colorIndex func(index int) uint8) {
+	colours := len(palette) - 1
+	if colours <= maxPalettedColours {
+		return palette, func(index int) uint8 {
+			return uint8(index + 1)
+		}
+	}
+	reduced = make(color.Palette, maxPalettedColours+1, maxPalettedColours+3)
+	reduced[0] = palette[0]
+	for i := 0; i < maxPalettedColours; i++ {
+		reduced[i+1] = palette[1+i*colours/maxPalettedColours]
+	}
+	return reduced, func(index int) uint8 {
+		return uint8(1 + index*maxPalettedColours/colours)
+	}
+}
+
+// drawAntMarker calls set for every pixel of the ant dot if the cell size is bigger than 5,
+// direction is true for the pixels of the line that shows where the ant is facing
+func drawAntMarker(ant *Ant, cellSize int, set func(x, y int, direction bool)) {
+	dimensions := ant.Dimensions()
+	cell := ant.Position
+	drawMarker(ant.Direction, cellSize, func(sx, sy int, direction bool) {
+		set(
+			int((cell.X-dimensions.BottomLeft.X)*int64(cellSize)+int64(sx)),
+			int((cell.Y-dimensions.BottomLeft.Y)*int64(cellSize)+int64(sy)),
+			direction,
+		)
+	})
+}
+
+// drawMarker calls set for every pixel of a dot facing the direction in a square of the given size,
+// sx and sy are relative to the corner of the square. Nothing is drawn if the size is not bigger than 5
+func drawMarker(facing Direction, size int, set func(sx, sy int, direction bool)) {
+	if size <= 5 {
+		return
+	}
+	for sx := 0; sx < size; sx++ {
+		for sy := 0; sy < size; sy++ {
+			radius := size / 2
+			if distance2From(sx, sy, radius, radius) <= (radius-1)*(radius-1) {
+				var direction bool
+				switch {
+				case facing == DirectionLeft && sx < radius && sy == radius:
+					direction = true
+				case facing == DirectionRight && sx > radius && sy == radius:
+					direction = true
+				case facing == DirectionTop && sx == radius && sy > radius:
+					direction = true
+				case facing == DirectionDown && sx == radius && sy < radius:
+					direction = true
 				}
+				set(sx, sy, direction)
 			}
 		}
 	}
-	return img
 }
 
 func distance2From(ax, ay, bx, by int) int {
@@ -96,3 +173,23 @@ func ToPalette(palette []colorful.Color) color.Palette {
 	}
 	return colorPalette
 }
+
+// maxSoftColours is the longest palette generated with colorful.SoftPalette, k-means gets too slow beyond it
+const maxSoftColours = 256
+
+// SoftPalette works as colorful.SoftPalette for short rules and falls back to GradientPalette for long ones
+func SoftPalette(colours int) ([]colorful.Color, error) {
+	if colours > maxSoftColours {
+		return GradientPalette(colours), nil
+	}
+	return colorful.SoftPalette(colours)
+}
+
+// GradientPalette returns colours evenly spread along the hue circle, it is fast for any number of colours
+func GradientPalette(colours int) []colorful.Color {
+	palette := make([]colorful.Color, colours)
+	for i := range palette {
+		palette[i] = colorful.Hcl(float64(i)*360/float64(colours), 0.6, 0.65).Clamped()
+	}
+	return palette
+}
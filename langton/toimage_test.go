@@ -3,7 +3,10 @@ package langton
 import (
 	"image/color"
 	"image/png"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/lucasb-eyer/go-colorful"
@@ -27,6 +30,12 @@ func TestToImage(t *testing.T) {
 		panic(err)
 	}
 
+	dir, err := ioutil.TempDir("", "toimage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
 	tests := []struct {
 		name string
 		args args
@@ -43,7 +52,7 @@ func TestToImage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := ToImage(tt.args.ant, tt.args.palette, 9)
-			file, err := os.Create("pic.png")
+			file, err := os.Create(filepath.Join(dir, "pic.png"))
 			if err != nil {
 				panic(err)
 			}
@@ -52,3 +61,67 @@ func TestToImage(t *testing.T) {
 		})
 	}
 }
+
+func TestToImage_LongRule(t *testing.T) {
+	rule := strings.Repeat("LRRL", 100)
+	ant := NewAntFromString(NewBoard(20), rule)
+	ant.NextN(5000)
+	// the ant only reaches the first steps, the last ones are seeded in a corner
+	high := []int{252, 253, 254, 300, 399}
+	for i, index := range high {
+		if err := ant.setStep(Point{X: -20 + int64(i), Y: -20}, ant.steps[index]); err != nil {
+			t.Fatalf("Ant.setStep() error = %v", err)
+		}
+	}
+	palette := ToPalette(GradientPalette(len(ant.steps)))
+
+	paletted := ToImage(ant, palette, 1)
+	if len(paletted.Palette) > 256 {
+		t.Fatalf("ToImage() palette has %d colours, want at most 256", len(paletted.Palette))
+	}
+	for i, index := range high {
+		want := 1 + index*maxPalettedColours/len(ant.steps)
+		got := int(paletted.ColorIndexAt(i, 0))
+		if got != want || paletted.Palette[got] != palette[1+(want-1)*len(ant.steps)/maxPalettedColours] {
+			t.Errorf("ToImage() step %d has colour %d, want %d", index, got, want)
+		}
+	}
+	if last := paletted.ColorIndexAt(len(high)-1, 0); last != maxPalettedColours {
+		t.Errorf("ToImage() last step has colour %d, want %d", last, maxPalettedColours)
+	}
+	rgba := ToRGBA(ant, palette, 1)
+	dimensions := ant.Dimensions()
+	ant.Board.Each(func(cell Cell) bool {
+		x := int(cell.X - dimensions.BottomLeft.X)
+		y := int(cell.Y - dimensions.BottomLeft.Y)
+		want := color.RGBAModel.Convert(palette[cell.Step.Index+1])
+		if got := rgba.At(x, y); got != want {
+			t.Fatalf("ToRGBA() at %v = %v, want %v", cell.Point, got, want)
+		}
+		if paletted.ColorIndexAt(x, y) == 0 {
+			t.Fatalf("ToImage() at %v is transparent", cell.Point)
+		}
+		return true
+	})
+}
+
+func TestToImage_Offset(t *testing.T) {
+	// a board away from the origin and wider than tall
+	ant := NewAntFromString(NewDimensions(100, 50, 129, 59), "LLRR")
+	ant.NextN(2000)
+	palette := ToPalette(GradientPalette(len(ant.steps)))
+
+	img := ToImage(ant, palette, 1)
+	if got := img.Bounds().Size(); got.X != 30 || got.Y != 10 {
+		t.Fatalf("ToImage() size = %v, want 30x10", got)
+	}
+	ant.Board.Each(func(cell Cell) bool {
+		if cell.Point == ant.Position.Point {
+			return true
+		}
+		if got := img.ColorIndexAt(int(cell.X-100), int(cell.Y-50)); int(got) != cell.Step.Index+1 {
+			t.Fatalf("ToImage() at %v = %d, want %d", cell.Point, got, cell.Step.Index+1)
+		}
+		return true
+	})
+}
@@ -0,0 +1,322 @@
+package langton
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// trajectoryMagic identifies the binary trajectory format
+const trajectoryMagic = "ANTT"
+
+// bitsPerMove is the number of bits used to store each action
+const bitsPerMove = 2
+
+// maxTrajectoryLength is the longest trajectory that can be read, 64GB of moves
+const maxTrajectoryLength = 1 << 38
+
+// maxTrajectorySide bounds the corners of the board read from a trajectory
+const maxTrajectorySide = 1 << 30
+
+var (
+	ErrTrajectoryEnd     = errors.New("End of the trajectory reached")
+	ErrTrajectoryStarted = errors.New("Trajectories must be recorded from a new ant")
+	ErrInvalidTrajectory = errors.New("Invalid trajectory")
+)
+
+// TrajectoryHeader holds the rule and starting conditions of a recorded run
+type TrajectoryHeader struct {
+	Steps      string
+	Dimensions Dimensions
+	Start      Point
+	Direction  Direction
+}
+
+// Trajectory is a compact log of the actions taken by an ant, each step takes 2 bits
+type Trajectory struct {
+	Header TrajectoryHeader
+
+	moves  []byte
+	length int64
+}
+
+// NewTrajectory creates an empty trajectory with the initial conditions of the given ant.
+// It fails if the ant has already moved because the board is not stored, if the ant follows a turmite
+// because the replay writes the next colour of the steps instead of following the states of the turmite,
+// and if the ant has noise or a schedule because the changes they make to the board are not recorded
+func NewTrajectory(ant *Ant) (*Trajectory, error) {
+	if ant.TotalSteps() != 0 {
+		return nil, ErrTrajectoryStarted
+	}
+	if ant.turmite != nil {
+		return nil, fmt.Errorf("%w: turmites can not be replayed", ErrInvalidTrajectory)
+	}
+	if ant.noise != nil || ant.schedule != nil {
+		return nil, fmt.Errorf("%w: noise and schedules can not be replayed", ErrInvalidTrajectory)
+	}
+	return &Trajectory{
+		Header: TrajectoryHeader{
+			Steps:      ant.Steps().String(),
+			Dimensions: ant.Dimensions(),
+			Start:      ant.Position.Point,
+			Direction:  ant.Direction,
+		},
+	}, nil
+}
+
+// Record moves the ant one step and appends the action taken to the trajectory
+func (trajectory *Trajectory) Record(ant *Ant) (*Cell, error) {
+	cell, err := ant.Next()
+	if err != nil {
+		return cell, err
+	}
+	trajectory.Append(ant.LastAction())
+	return cell, nil
+}
+
+// Append adds an action at the end of the trajectory
+func (trajectory *Trajectory) Append(action Action) {
+	code, ok := moveCodes[action]
+	if !ok {
+		panic("Invalid action provided")
+	}
+	bit := trajectory.length * bitsPerMove
+	if bit/8 >= int64(len(trajectory.moves)) {
+		trajectory.moves = append(trajectory.moves, 0)
+	}
+	trajectory.moves[bit/8] |= code << uint(bit%8)
+	trajectory.length++
+}
+
+// Len returns the number of recorded steps
+func (trajectory *Trajectory) Len() int64 {
+	return trajectory.length
+}
+
+// At returns the action taken at step i
+func (trajectory *Trajectory) At(i int64) Action {
+	if i < 0 || i >= trajectory.length {
+		panic("step out of the trajectory")
+	}
+	bit := i * bitsPerMove
+	code := (trajectory.moves[bit/8] >> uint(bit%8)) & (1<<bitsPerMove - 1)
+	return moveActions[code]
+}
+
+// Diff returns the first step where both trajectories differ, or -1 if they are equal.
+// If one trajectory is a prefix of the other, the length of the shortest is returned
+func (trajectory *Trajectory) Diff(other *Trajectory) int64 {
+	length := trajectory.length
+	if other.length < length {
+		length = other.length
+	}
+	for i := int64(0); i < length; i++ {
+		if trajectory.At(i) != other.At(i) {
+			return i
+		}
+	}
+	if trajectory.length != other.length {
+		return length
+	}
+	return -1
+}
+
+// WriteTo writes the trajectory in binary format
+func (trajectory *Trajectory) WriteTo(w io.Writer) (int64, error) {
+	header := trajectory.Header
+	fields := []interface{}{
+		[]byte(trajectoryMagic),
+		int64(len(header.Steps)),
+		[]byte(header.Steps),
+		header.Dimensions.BottomLeft,
+		header.Dimensions.TopRight,
+		header.Start,
+		int64(header.Direction),
+		trajectory.length,
+		trajectory.moves,
+	}
+	counter := &countingWriter{w: w}
+	for _, field := range fields {
+		err := binary.Write(counter, binary.LittleEndian, field)
+		if err != nil {
+			return counter.n, err
+		}
+	}
+	return counter.n, nil
+}
+
+// ReadTrajectory reads a trajectory written with WriteTo
+func ReadTrajectory(r io.Reader) (*Trajectory, error) {
+	magic := make([]byte, len(trajectoryMagic))
+	err := binary.Read(r, binary.LittleEndian, magic)
+	if err != nil {
+		return nil, err
+	}
+	if string(magic) != trajectoryMagic {
+		return nil, ErrInvalidTrajectory
+	}
+
+	var stepsLength int64
+	err = binary.Read(r, binary.LittleEndian, &stepsLength)
+	if err != nil {
+		return nil, err
+	}
+	if stepsLength <= 0 || stepsLength > 1<<20 {
+		return nil, fmt.Errorf("%w: rule length %d", ErrInvalidTrajectory, stepsLength)
+	}
+	steps := make([]byte, stepsLength)
+
+	var (
+		bottomLeft, topRight, start Point
+		direction, length           int64
+	)
+	for _, field := range []interface{}{steps, &bottomLeft, &topRight, &start, &direction, &length} {
+		err = binary.Read(r, binary.LittleEndian, field)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if direction < 0 || direction >= int64(DirectionInvalid) {
+		return nil, ErrInvalidTrajectory
+	}
+	if length < 0 || length > maxTrajectoryLength {
+		return nil, fmt.Errorf("%w: length %d", ErrInvalidTrajectory, length)
+	}
+	for _, p := range []Point{bottomLeft, topRight} {
+		if p.X < -maxTrajectorySide || p.X > maxTrajectorySide || p.Y < -maxTrajectorySide || p.Y > maxTrajectorySide {
+			return nil, fmt.Errorf("%w: corner %s out of range", ErrInvalidTrajectory, p)
+		}
+	}
+	if bottomLeft.X > topRight.X || bottomLeft.Y > topRight.Y {
+		return nil, fmt.Errorf("%w: empty board %s %s", ErrInvalidTrajectory, bottomLeft, topRight)
+	}
+	_, err = ParseSteps(string(steps))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTrajectory, err)
+	}
+
+	// the moves are read as they arrive, so a wrong length fails at the end of the input instead of allocating it
+	moves := &bytes.Buffer{}
+	_, err = io.CopyN(moves, r, (length*bitsPerMove+7)/8)
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: %d steps recorded, want %d", ErrInvalidTrajectory, int64(moves.Len())*8/bitsPerMove, length)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Trajectory{
+		Header: TrajectoryHeader{
+			Steps:      string(steps),
+			Dimensions: NewDimensions(bottomLeft.X, bottomLeft.Y, topRight.X, topRight.Y),
+			Start:      start,
+			Direction:  Direction(direction),
+		},
+		moves:  moves.Bytes(),
+		length: length,
+	}, nil
+}
+
+// Replayer rebuilds the states of a recorded run without evaluating the rule
+type Replayer struct {
+	trajectory *Trajectory
+	ant        *Ant
+}
+
+// NewReplayer creates a replayer positioned at the beginning of the trajectory
+func NewReplayer(trajectory *Trajectory) (*Replayer, error) {
+	replayer := &Replayer{
+		trajectory: trajectory,
+	}
+	err := replayer.Reset()
+	if err != nil {
+		return nil, err
+	}
+	return replayer, nil
+}
+
+// Reset moves the replayer back to the beginning of the trajectory.
+// The ant walks a SparseBoard, the board the trajectory was recorded on is not stored and may be too big to allocate
+func (replayer *Replayer) Reset() error {
+	header := replayer.trajectory.Header
+	if !header.Dimensions.Contains(header.Start) {
+		return fmt.Errorf("%w: start %s is out of bounds", ErrInvalidTrajectory, header.Start)
+	}
+	ant := NewAntOnBoard(NewSparseBoard(header.Dimensions), StepsFromString(header.Steps)...)
+	if header.Start != ant.Position.Point {
+		ant.setCell(Cell{
+			Point: ant.Position.Point,
+		})
+		ant.Position, _, _ = ant.ensureCellAt(header.Start)
+		ant.setCell(ant.Position)
+	}
+	ant.Direction = header.Direction
+	replayer.ant = ant
+	return nil
+}
+
+// Ant returns the ant with the state reached so far.
+// It is modified in place by subsequent calls to the replayer
+func (replayer *Replayer) Ant() *Ant {
+	return replayer.ant
+}
+
+// Next applies the next recorded action and returns the new position and the action taken
+func (replayer *Replayer) Next() (*Cell, Action, error) {
+	step := replayer.ant.TotalSteps()
+	if step >= replayer.trajectory.Len() {
+		return &replayer.ant.Position, ActionNone, ErrTrajectoryEnd
+	}
+	action := replayer.trajectory.At(step)
+	cell, err := replayer.ant.move(action)
+	return cell, action, err
+}
+
+// StepTo rebuilds the state after the given number of steps
+func (replayer *Replayer) StepTo(step int64) (*Ant, error) {
+	if step < 0 || step > replayer.trajectory.Len() {
+		return nil, ErrTrajectoryEnd
+	}
+	if step < replayer.ant.TotalSteps() {
+		err := replayer.Reset()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for replayer.ant.TotalSteps() < step {
+		_, _, err := replayer.Next()
+		if err != nil {
+			return replayer.ant, err
+		}
+	}
+	return replayer.ant, nil
+}
+
+var (
+	moveCodes = map[Action]byte{
+		ActionTurnLeft:  0,
+		ActionTurnRight: 1,
+		ActionStraight:  2,
+		ActionUTurn:     3,
+	}
+	moveActions = [1 << bitsPerMove]Action{
+		ActionTurnLeft,
+		ActionTurnRight,
+		ActionStraight,
+		ActionUTurn,
+	}
+)
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (counter *countingWriter) Write(p []byte) (int, error) {
+	n, err := counter.w.Write(p)
+	counter.n += int64(n)
+	return n, err
+}
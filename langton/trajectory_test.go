@@ -0,0 +1,185 @@
+package langton
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestTrajectory_Replay(t *testing.T) {
+	ant := NewAntFromString(NewBoard(20), "RLLLLRRRLLL")
+	trajectory, err := NewTrajectory(ant)
+	if err != nil {
+		t.Fatalf("NewTrajectory() error = %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		_, err := trajectory.Record(ant)
+		if err != nil {
+			break
+		}
+	}
+	if trajectory.Len() != ant.TotalSteps() {
+		t.Fatalf("Trajectory.Len() = %d, want %d", trajectory.Len(), ant.TotalSteps())
+	}
+
+	buf := &bytes.Buffer{}
+	_, err = trajectory.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("Trajectory.WriteTo() error = %v", err)
+	}
+	if maxSize := int(trajectory.Len()/4) + 100; buf.Len() > maxSize {
+		t.Errorf("Trajectory.WriteTo() wrote %d bytes, want less than %d", buf.Len(), maxSize)
+	}
+
+	read, err := ReadTrajectory(buf)
+	if err != nil {
+		t.Fatalf("ReadTrajectory() error = %v", err)
+	}
+	if diff := read.Diff(trajectory); diff != -1 {
+		t.Errorf("ReadTrajectory() differs at step %d", diff)
+	}
+
+	replayer, err := NewReplayer(read)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	replayed, err := replayer.StepTo(read.Len())
+	if err != nil {
+		t.Fatalf("Replayer.StepTo() error = %v", err)
+	}
+	if replayed.String() != ant.String() {
+		t.Errorf("Replayer.StepTo() = \n%s, want \n%s", replayed, ant)
+	}
+
+	reference := NewAntFromString(NewBoard(20), "RLLLLRRRLLL")
+	reference.NextN(100)
+	replayed, err = replayer.StepTo(100)
+	if err != nil {
+		t.Fatalf("Replayer.StepTo() error = %v", err)
+	}
+	if replayed.String() != reference.String() || replayed.Direction != reference.Direction {
+		t.Errorf("Replayer.StepTo(100) = \n%s, want \n%s", replayed, reference)
+	}
+}
+
+func TestReadTrajectory_Length(t *testing.T) {
+	ant := NewAntFromString(NewBoard(20), "LR")
+	trajectory, err := NewTrajectory(ant)
+	if err != nil {
+		t.Fatalf("NewTrajectory() error = %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		trajectory.Record(ant)
+	}
+	buf := &bytes.Buffer{}
+	trajectory.WriteTo(buf)
+	data := buf.Bytes()
+	// the length is written just before the 25 bytes of moves
+	offset := len(data) - 25 - 8
+
+	for _, length := range []int64{-1, 1 << 62, 101} {
+		corrupted := append([]byte{}, data...)
+		binary.LittleEndian.PutUint64(corrupted[offset:], uint64(length))
+		if _, err := ReadTrajectory(bytes.NewReader(corrupted)); !errors.Is(err, ErrInvalidTrajectory) {
+			t.Errorf("ReadTrajectory() with length %d error = %v, want %v", length, err, ErrInvalidTrajectory)
+		}
+	}
+}
+
+func TestReadTrajectory_Dimensions(t *testing.T) {
+	huge := NewDimensions(-1<<29, -1<<29, 1<<29, 1<<29)
+	ant := NewAntOnBoard(NewSparseBoard(huge), StepsFromString("LR")...)
+	trajectory, err := NewTrajectory(ant)
+	if err != nil {
+		t.Fatalf("NewTrajectory() error = %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		trajectory.Record(ant)
+	}
+	buf := &bytes.Buffer{}
+	trajectory.WriteTo(buf)
+	data := buf.Bytes()
+
+	// a board too big for a DenseBoard is replayed anyway
+	read, err := ReadTrajectory(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadTrajectory() error = %v", err)
+	}
+	replayer, err := NewReplayer(read)
+	if err != nil {
+		t.Fatalf("NewReplayer() error = %v", err)
+	}
+	replayed, err := replayer.StepTo(read.Len())
+	if err != nil {
+		t.Fatalf("Replayer.StepTo() error = %v", err)
+	}
+	if !replayed.Equal(ant) {
+		t.Errorf("Replayer.StepTo() differs in %v", replayed.Diff(ant))
+	}
+
+	// the bottom left corner follows the magic and the rule
+	offset := len(trajectoryMagic) + 8 + len("LR")
+	for _, x := range []int64{-1 << 40, 1<<29 + 1} {
+		corrupted := append([]byte{}, data...)
+		binary.LittleEndian.PutUint64(corrupted[offset:], uint64(x))
+		if _, err := ReadTrajectory(bytes.NewReader(corrupted)); !errors.Is(err, ErrInvalidTrajectory) {
+			t.Errorf("ReadTrajectory() with corner x %d error = %v, want %v", x, err, ErrInvalidTrajectory)
+		}
+	}
+}
+
+func TestTrajectory_Turmite(t *testing.T) {
+	turmite, err := ParseTurmite("{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+	if err != nil {
+		t.Fatalf("ParseTurmite() error = %v", err)
+	}
+	ant, err := NewTurmiteAnt(NewBoard(20), turmite)
+	if err != nil {
+		t.Fatalf("NewTurmiteAnt() error = %v", err)
+	}
+	if _, err := NewTrajectory(ant); !errors.Is(err, ErrInvalidTrajectory) {
+		t.Errorf("NewTrajectory() error = %v, want %v", err, ErrInvalidTrajectory)
+	}
+}
+
+func TestTrajectory_NoiseAndSchedule(t *testing.T) {
+	noisy := NewAntFromString(NewBoard(20), "RL")
+	err := noisy.SetNoise(FlipNoise{Every: 10, Radius: 2})
+	if err != nil {
+		t.Fatalf("SetNoise() error = %v", err)
+	}
+	if _, err := NewTrajectory(noisy); !errors.Is(err, ErrInvalidTrajectory) {
+		t.Errorf("NewTrajectory() with noise error = %v, want %v", err, ErrInvalidTrajectory)
+	}
+
+	scheduled := NewAntFromString(NewBoard(20), "RL")
+	schedule, err := ParseSchedule("RL:10,LLRR:10")
+	if err != nil {
+		t.Fatalf("ParseSchedule() error = %v", err)
+	}
+	err = scheduled.SetSchedule(schedule)
+	if err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+	if _, err := NewTrajectory(scheduled); !errors.Is(err, ErrInvalidTrajectory) {
+		t.Errorf("NewTrajectory() with a schedule error = %v, want %v", err, ErrInvalidTrajectory)
+	}
+}
+
+func TestTrajectory_Diff(t *testing.T) {
+	a := &Trajectory{}
+	b := &Trajectory{}
+	for _, action := range "LRRSL" {
+		a.Append(Action(action))
+	}
+	for _, action := range "LRLSL" {
+		b.Append(Action(action))
+	}
+	if got := a.Diff(b); got != 2 {
+		t.Errorf("Trajectory.Diff() = %d, want %d", got, 2)
+	}
+	if got := a.Diff(a); got != -1 {
+		t.Errorf("Trajectory.Diff() = %d, want %d", got, -1)
+	}
+}
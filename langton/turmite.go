@@ -0,0 +1,302 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TurmiteRule is what a turmite does on a cell of a given colour while it is in a given state
+type TurmiteRule struct {
+	// Colour written in the cell
+	Colour int
+	// Turn taken before moving
+	Turn Action
+	// State of the turmite after the step
+	State int
+}
+
+// Turmite is an ant with internal states, Rules[state][colour] is followed on a cell of that colour.
+// Colours are step indexes, so a turmite with a single state that writes the next colour is a regular ant
+type Turmite struct {
+	Rules [][]TurmiteRule
+}
+
+var ErrInvalidTurmite = errors.New("Invalid turmite")
+
+// ParseTurmite parses the nested brace notation used by Ed Pegg and Wolfram, "{{{1,2,0},{0,8,0}}}".
+// Each state is a list of {colour, turn, state} triples, one per colour, and turns are 1 straight, 2 right, 4 u-turn and 8 left
+func ParseTurmite(notation string) (*Turmite, error) {
+	parser := turmiteParser{input: notation}
+	turmite := &Turmite{}
+	err := parser.list(func() error {
+		state := []TurmiteRule{}
+		err := parser.list(func() error {
+			values := [3]int{}
+			i := 0
+			err := parser.list(func() error {
+				if i == len(values) {
+					return parser.errorf("a rule has 3 values")
+				}
+				value, err := parser.number()
+				values[i] = value
+				i++
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if i != len(values) {
+				return parser.errorf("a rule has 3 values")
+			}
+			turn, err := gollyAction(values[1])
+			if err != nil {
+				return parser.errorf("unknown turn %d", values[1])
+			}
+			state = append(state, TurmiteRule{
+				Colour: values[0],
+				Turn:   turn,
+				State:  values[2],
+			})
+			return nil
+		})
+		turmite.Rules = append(turmite.Rules, state)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpaces()
+	if parser.offset != len(parser.input) {
+		return nil, parser.errorf("unexpected %q", parser.input[parser.offset:])
+	}
+	return turmite, turmite.Validate()
+}
+
+// turmiteParser reads the brace notation keeping track of the offset for the errors
+type turmiteParser struct {
+	input  string
+	offset int
+}
+
+func (parser *turmiteParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidTurmite, fmt.Sprintf(format, args...), parser.offset)
+}
+
+func (parser *turmiteParser) skipSpaces() {
+	for parser.offset < len(parser.input) && strings.ContainsRune(" \t\r\n", rune(parser.input[parser.offset])) {
+		parser.offset++
+	}
+}
+
+// expect consumes the character c, ok is false if the next character is a different one
+func (parser *turmiteParser) expect(c byte) bool {
+	parser.skipSpaces()
+	if parser.offset < len(parser.input) && parser.input[parser.offset] == c {
+		parser.offset++
+		return true
+	}
+	return false
+}
+
+// list parses a comma separated list between braces calling item for each element
+func (parser *turmiteParser) list(item func() error) error {
+	if !parser.expect('{') {
+		return parser.errorf("expected '{'")
+	}
+	for {
+		err := item()
+		if err != nil {
+			return err
+		}
+		if parser.expect('}') {
+			return nil
+		}
+		if !parser.expect(',') {
+			return parser.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+func (parser *turmiteParser) number() (int, error) {
+	parser.skipSpaces()
+	start := parser.offset
+	for parser.offset < len(parser.input) && parser.input[parser.offset] >= '0' && parser.input[parser.offset] <= '9' {
+		parser.offset++
+	}
+	if start == parser.offset {
+		return 0, parser.errorf("expected a number")
+	}
+	return strconv.Atoi(parser.input[start:parser.offset])
+}
+
+// Validate checks that every state has a rule per colour and that colours and states exist
+func (turmite *Turmite) Validate() error {
+	if len(turmite.Rules) == 0 || len(turmite.Rules[0]) == 0 {
+		return fmt.Errorf("%w: no rules", ErrInvalidTurmite)
+	}
+	colours := len(turmite.Rules[0])
+	for s, state := range turmite.Rules {
+		if len(state) != colours {
+			return fmt.Errorf("%w: state %d has %d colours, want %d", ErrInvalidTurmite, s, len(state), colours)
+		}
+		for c, rule := range state {
+			if rule.Colour < 0 || rule.Colour >= colours {
+				return fmt.Errorf("%w: state %d colour %d writes the colour %d that does not exist", ErrInvalidTurmite, s, c, rule.Colour)
+			}
+			if rule.State < 0 || rule.State >= len(turmite.Rules) {
+				return fmt.Errorf("%w: state %d colour %d moves to the state %d that does not exist", ErrInvalidTurmite, s, c, rule.State)
+			}
+			if _, err := gollyTurn(rule.Turn); err != nil {
+				return fmt.Errorf("%w: state %d colour %d has an invalid turn", ErrInvalidTurmite, s, c)
+			}
+		}
+	}
+	return nil
+}
+
+// States returns the number of states
+func (turmite *Turmite) States() int {
+	return len(turmite.Rules)
+}
+
+// Colours returns the number of colours
+func (turmite *Turmite) Colours() int {
+	if len(turmite.Rules) == 0 {
+		return 0
+	}
+	return len(turmite.Rules[0])
+}
+
+// String returns the turmite in the format accepted by ParseTurmite
+func (turmite *Turmite) String() string {
+	builder := strings.Builder{}
+	builder.WriteRune('{')
+	for s, state := range turmite.Rules {
+		if s != 0 {
+			builder.WriteRune(',')
+		}
+		builder.WriteRune('{')
+		for c, rule := range state {
+			if c != 0 {
+				builder.WriteRune(',')
+			}
+			turn, _ := gollyTurn(rule.Turn)
+			fmt.Fprintf(&builder, "{%d,%d,%d}", rule.Colour, turn, rule.State)
+		}
+		builder.WriteRune('}')
+	}
+	builder.WriteRune('}')
+	return builder.String()
+}
+
+// Steps returns the equivalent steps of a turmite with a single state that writes the next colour
+func (turmite *Turmite) Steps() (Steps, error) {
+	if turmite.States() != 1 {
+		return nil, fmt.Errorf("%w: only turmites with a single state can be written as steps", ErrInvalidTurmite)
+	}
+	colours := turmite.Colours()
+	steps := make(Steps, colours)
+	for c, rule := range turmite.Rules[0] {
+		if rule.Colour != (c+1)%colours {
+			return nil, fmt.Errorf("%w: colour %d does not write the next colour", ErrInvalidTurmite, c)
+		}
+		steps[c] = Step{
+			Action: rule.Turn,
+		}
+	}
+	steps.Numerate()
+	return steps, nil
+}
+
+// TurmiteFromSteps returns the turmite with a single state that behaves as the steps
+func TurmiteFromSteps(steps Steps) (*Turmite, error) {
+	state := make([]TurmiteRule, len(steps))
+	for i, step := range steps {
+		if step.Stochastic() {
+			return nil, fmt.Errorf("%w: stochastic steps can not be written as a turmite", ErrInvalidTurmite)
+		}
+		state[i] = TurmiteRule{
+			Colour: (i + 1) % len(steps),
+			Turn:   step.Action,
+		}
+	}
+	turmite := &Turmite{
+		Rules: [][]TurmiteRule{state},
+	}
+	return turmite, turmite.Validate()
+}
+
+// turmiteState tracks the state of an ant that follows a Turmite
+type turmiteState struct {
+	turmite *Turmite
+	state   int
+}
+
+// rule returns the rule for a cell with the given colour in the current state
+func (state *turmiteState) rule(colour int) TurmiteRule {
+	return state.turmite.Rules[state.state][colour]
+}
+
+// NewTurmiteAnt creates an ant in a DenseBoard that follows the turmite.
+// The steps of the ant have a colour per turmite colour with the turns of the first state
+func NewTurmiteAnt(dimensions Dimensions, turmite *Turmite) (*Ant, error) {
+	err := turmite.Validate()
+	if err != nil {
+		return nil, err
+	}
+	ant := NewAnt(dimensions, turmite.Turns()...)
+	return ant, ant.SetTurmite(turmite)
+}
+
+// Turns returns a step per colour with the turns of the first state, the steps of an ant that follows the turmite
+func (turmite *Turmite) Turns() Steps {
+	steps := make(Steps, turmite.Colours())
+	for c, rule := range turmite.Rules[0] {
+		steps[c] = Step{
+			Action: rule.Turn,
+		}
+	}
+	steps.Numerate()
+	return steps
+}
+
+// SetTurmite makes the ant follow the turmite starting in the state 0, nil removes it.
+// The turmite must have as many colours as steps the ant
+func (ant *Ant) SetTurmite(turmite *Turmite) error {
+	if turmite == nil {
+		ant.turmite = nil
+		return nil
+	}
+	err := turmite.Validate()
+	if err != nil {
+		return err
+	}
+	if turmite.Colours() != len(ant.steps) {
+		return fmt.Errorf("%w: the turmite has %d colours and the ant %d steps", ErrInvalidTurmite, turmite.Colours(), len(ant.steps))
+	}
+	if ant.schedule != nil || ant.sensing != nil {
+		return fmt.Errorf("%w: a turmite can not follow a schedule or a sensing rule", ErrInvalidTurmite)
+	}
+	ant.turmite = &turmiteState{
+		turmite: turmite,
+	}
+	return nil
+}
+
+// Turmite returns the turmite followed by the ant, nil without turmite
+func (ant *Ant) Turmite() *Turmite {
+	if ant.turmite == nil {
+		return nil
+	}
+	return ant.turmite.turmite
+}
+
+// TurmiteState returns the state of the turmite followed by the ant, -1 without turmite
+func (ant *Ant) TurmiteState() int {
+	if ant.turmite == nil {
+		return -1
+	}
+	return ant.turmite.state
+}
@@ -0,0 +1,155 @@
+package langton
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTurmite(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     string
+		err      bool
+	}{
+		{"{{{1,2,0},{0,8,0}}}", "{{{1,2,0},{0,8,0}}}", false},
+		{" { { {1, 8, 1}, {1, 8, 1} }, { {1, 2, 1}, {0, 1, 0} } } ", "{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}", false},
+		{"{{{1,4,0},{0,1,0}}}", "{{{1,4,0},{0,1,0}}}", false},
+		{"{{{1,2,0},{0,8,0}}", "", true},
+		{"{{{1,2,0},{0,8}}}", "", true},
+		{"{{{1,2,0,1},{0,8,0}}}", "", true},
+		{"{{{1,3,0},{0,8,0}}}", "", true},
+		{"{{{2,2,0},{0,8,0}}}", "", true},
+		{"{{{1,2,1},{0,8,0}}}", "", true},
+		{"{{{1,2,0},{0,8,0}},{{1,2,0}}}", "", true},
+		{"{{{1,2,0},{0,8,0}}}x", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := ParseTurmite(tt.notation)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidTurmite) {
+					t.Errorf("ParseTurmite() error = %v, want %v", err, ErrInvalidTurmite)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTurmite() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Turmite.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTurmite_Steps(t *testing.T) {
+	turmite, err := ParseTurmite("{{{1,2,0},{2,8,0},{0,1,0}}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps, err := turmite.Steps()
+	if err != nil || steps.String() != "RLS" {
+		t.Errorf("Turmite.Steps() = %v, %v, want %v", steps, err, "RLS")
+	}
+	back, err := TurmiteFromSteps(steps)
+	if err != nil || back.String() != turmite.String() {
+		t.Errorf("TurmiteFromSteps() = %v, %v, want %v", back, err, turmite)
+	}
+
+	ant, err := NewTurmiteAnt(NewBoard(20), turmite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewAnt(NewBoard(20), steps...)
+	ant.NextN(500)
+	want.NextN(500)
+	if ant.String() != want.String() || !ant.Equal(want) {
+		t.Errorf("turmite ant = \n%s, want \n%s", ant, want)
+	}
+
+	multi, _ := ParseTurmite("{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+	if _, err := multi.Steps(); err == nil {
+		t.Errorf("Turmite.Steps() error = nil, want error")
+	}
+}
+
+func TestTurmite_Walk(t *testing.T) {
+	// Fibonacci spiral
+	turmite, err := ParseTurmite("{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ant, err := NewTurmiteAnt(NewBoard(100), turmite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reference implementation with a map of colours
+	colours := map[Point]int{}
+	position := Point{}
+	direction := DirectionTop
+	state := 0
+	for i := 0; i < 3000; i++ {
+		rule := turmite.Rules[state][colours[position]]
+		direction = direction.Turn(rule.Turn)
+		colours[position] = rule.Colour
+		state = rule.State
+		position = position.Walk(direction)
+
+		if _, err := ant.Next(); err != nil {
+			t.Fatalf("step %d: Ant.Next() error = %v", i, err)
+		}
+	}
+	if ant.Position.Point != position || ant.Direction != direction || ant.TurmiteState() != state {
+		t.Fatalf("Ant = %v %v %d, want %v %v %d", ant.Position.Point, ant.Direction, ant.TurmiteState(), position, direction, state)
+	}
+	for p, colour := range colours {
+		cell, err := ant.CellAt(p)
+		if err != nil || cell.Step.Index != colour {
+			t.Fatalf("Ant.CellAt(%v) = %v, %v, want colour %d", p, cell, err, colour)
+		}
+	}
+}
+
+func TestAnt_SetTurmite_Combinations(t *testing.T) {
+	turmite, err := ParseTurmite("{{{1,8,1},{1,8,1}},{{1,2,1},{0,1,0}}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := ParseSchedule("LR:10,RL:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule, err := ParseSensingRule("ahead *1:S")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ant, err := NewTurmiteAnt(NewBoard(20), turmite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ant.SetSchedule(schedule); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("SetSchedule() on a turmite error = %v, want %v", err, ErrInvalidSchedule)
+	}
+	if err := ant.SetSensingRule(rule); !errors.Is(err, ErrInvalidSensingRule) {
+		t.Errorf("SetSensingRule() on a turmite error = %v, want %v", err, ErrInvalidSensingRule)
+	}
+
+	ant = NewAntFromString(NewBoard(20), "LR")
+	if err := ant.SetSchedule(schedule); err != nil {
+		t.Fatalf("SetSchedule() error = %v", err)
+	}
+	if err := ant.SetTurmite(turmite); !errors.Is(err, ErrInvalidTurmite) {
+		t.Errorf("SetTurmite() with a schedule error = %v, want %v", err, ErrInvalidTurmite)
+	}
+
+	ant = NewAntFromString(NewBoard(20), "LR")
+	if err := ant.SetSensingRule(rule); err != nil {
+		t.Fatalf("SetSensingRule() error = %v", err)
+	}
+	if err := ant.SetTurmite(turmite); !errors.Is(err, ErrInvalidTurmite) {
+		t.Errorf("SetTurmite() with a sensing rule error = %v, want %v", err, ErrInvalidTurmite)
+	}
+}
@@ -0,0 +1,211 @@
+package langton
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+
+	"golang.org/x/image/colornames"
+)
+
+var ErrInvalidViewport = errors.New("Invalid viewport")
+
+// Reduction chooses the colour of a pixel that covers more than one cell
+type Reduction int
+
+const (
+	// ReduceMajority paints the most frequent colour, unvisited cells are counted as the background
+	ReduceMajority Reduction = iota
+	// ReduceAverage paints the mean of the colours, unvisited cells are counted as the background
+	ReduceAverage
+	// ReduceAnyVisited paints the most frequent colour of the visited cells, so thin trails do not disappear
+	ReduceAnyVisited
+	// ReduceInvalid is an invalid reduction
+	ReduceInvalid
+)
+
+var reductionNames = [ReduceInvalid]string{
+	"majority",
+	"average",
+	"any",
+}
+
+// String returns the name of the reduction
+func (reduction Reduction) String() string {
+	if reduction < 0 || reduction >= ReduceInvalid {
+		return "invalid"
+	}
+	return reductionNames[reduction]
+}
+
+// ParseReduction returns the reduction with the given name, majority, average or any
+func ParseReduction(name string) (Reduction, error) {
+	for i, reductionName := range reductionNames {
+		if reductionName == name {
+			return Reduction(i), nil
+		}
+	}
+	return ReduceInvalid, fmt.Errorf("%w: unknown reduction %q", ErrInvalidViewport, name)
+}
+
+// Viewport renders a rectangle of the board in an image of any size.
+// Each pixel covers the cells between its edges, so the same viewport scales up, where a cell takes several pixels,
+// and scales down, where a pixel takes several cells and the Reduction chooses its colour.
+// As in ToImage, the bottom left corner of the World is the first pixel of the image
+type Viewport struct {
+	// World is the area of the board drawn, it can go beyond the board and the visited cells
+	World Dimensions
+	// Width and Height are the size of the image in pixels
+	Width  int
+	Height int
+	// Reduction is used for the pixels that cover more than one cell
+	Reduction Reduction
+}
+
+// FitViewport returns a viewport of the world that fits in the given pixels keeping the aspect ratio
+func FitViewport(world Dimensions, maxWidth int, maxHeight int, reduction Reduction) Viewport {
+	scale := float64(maxWidth) / float64(world.Width())
+	if s := float64(maxHeight) / float64(world.Height()); s < scale {
+		scale = s
+	}
+	viewport := Viewport{
+		World:     world,
+		Width:     int(float64(world.Width())*scale + 0.5),
+		Height:    int(float64(world.Height())*scale + 0.5),
+		Reduction: reduction,
+	}
+	if viewport.Width < 1 {
+		viewport.Width = 1
+	}
+	if viewport.Height < 1 {
+		viewport.Height = 1
+	}
+	return viewport
+}
+
+// Render draws the viewport of the ant with the palette, the palette index 0 is the background.
+// The ant is drawn as in ToImage when a cell takes more than 5 pixels
+func (viewport Viewport) Render(ant *Ant, palette color.Palette) (*image.RGBA, error) {
+	if viewport.Width <= 0 || viewport.Height <= 0 {
+		return nil, fmt.Errorf("%w: image of %dx%d pixels", ErrInvalidViewport, viewport.Width, viewport.Height)
+	}
+	if viewport.World.Width() <= 0 || viewport.World.Height() <= 0 {
+		return nil, fmt.Errorf("%w: empty world %s", ErrInvalidViewport, &viewport.World)
+	}
+	if viewport.Reduction < 0 || viewport.Reduction >= ReduceInvalid {
+		return nil, fmt.Errorf("%w: reduction %d", ErrInvalidViewport, viewport.Reduction)
+	}
+	if len(palette) < len(ant.steps)+1 {
+		return nil, fmt.Errorf("%w: %d colours for %d steps", ErrInvalidViewport, len(palette)-1, len(ant.steps))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, viewport.Width, viewport.Height))
+	counts := make([]int64, len(palette))
+	for py := 0; py < viewport.Height; py++ {
+		minY, maxY := viewport.rows(py)
+		for px := 0; px < viewport.Width; px++ {
+			minX, maxX := viewport.columns(px)
+			for i := range counts {
+				counts[i] = 0
+			}
+			var visited int64
+			ant.Region(NewDimensions(minX, minY, maxX, maxY), func(cell Cell) bool {
+				counts[cell.Step.Index+1]++
+				visited++
+				return true
+			})
+			counts[0] = (maxX-minX+1)*(maxY-minY+1) - visited
+			img.Set(px, py, viewport.reduce(counts, palette))
+		}
+	}
+
+	viewport.drawAnt(ant, img)
+	return img, nil
+}
+
+// columns returns the first and last cell columns covered by the pixel column px
+func (viewport Viewport) columns(px int) (int64, int64) {
+	return span(viewport.World.BottomLeft.X, viewport.World.Width(), int64(viewport.Width), int64(px))
+}
+
+// rows returns the first and last cell rows covered by the pixel row py
+func (viewport Viewport) rows(py int) (int64, int64) {
+	return span(viewport.World.BottomLeft.Y, viewport.World.Height(), int64(viewport.Height), int64(py))
+}
+
+// span returns the cells covered by the pixel i when cells are spread over pixels, it is never empty
+func span(first int64, cells int64, pixels int64, i int64) (int64, int64) {
+	start := first + i*cells/pixels
+	end := first + (i+1)*cells/pixels - 1
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// reduce returns the colour of a pixel from the number of cells of each palette index
+func (viewport Viewport) reduce(counts []int64, palette color.Palette) color.Color {
+	switch viewport.Reduction {
+	case ReduceAverage:
+		var r, g, b, a, total uint64
+		for i, count := range counts {
+			if count == 0 {
+				continue
+			}
+			cr, cg, cb, ca := palette[i].RGBA()
+			r += uint64(cr) * uint64(count)
+			g += uint64(cg) * uint64(count)
+			b += uint64(cb) * uint64(count)
+			a += uint64(ca) * uint64(count)
+			total += uint64(count)
+		}
+		return color.RGBA64{
+			R: uint16(r / total),
+			G: uint16(g / total),
+			B: uint16(b / total),
+			A: uint16(a / total),
+		}
+	case ReduceAnyVisited:
+		return palette[majority(counts, 1)]
+	default:
+		return palette[majority(counts, 0)]
+	}
+}
+
+// majority returns the index with the highest count from the given index, first if all of them are 0
+func majority(counts []int64, from int) int {
+	best := 0
+	for i := from; i < len(counts); i++ {
+		if counts[i] > counts[best] || best < from && counts[i] > 0 {
+			best = i
+		}
+	}
+	return best
+}
+
+// drawAnt draws the ant marker if the ant is in the viewport and its cell takes more than 5 pixels
+func (viewport Viewport) drawAnt(ant *Ant, img *image.RGBA) {
+	if !viewport.World.Contains(ant.Position.Point) {
+		return
+	}
+	minX, maxX := viewport.pixels(ant.Position.X-viewport.World.BottomLeft.X, viewport.World.Width(), viewport.Width)
+	minY, maxY := viewport.pixels(ant.Position.Y-viewport.World.BottomLeft.Y, viewport.World.Height(), viewport.Height)
+	size := maxX - minX
+	if maxY-minY < size {
+		size = maxY - minY
+	}
+	drawMarker(ant.Direction, size, func(sx, sy int, direction bool) {
+		if direction {
+			img.Set(minX+sx, minY+sy, colornames.Red)
+			return
+		}
+		img.Set(minX+sx, minY+sy, colornames.Black)
+	})
+}
+
+// pixels returns the first pixel and the end of the pixels covered by the cell i, the opposite of span
+func (viewport Viewport) pixels(i int64, cells int64, pixels int) (int, int) {
+	p := int64(pixels)
+	return int((i*p + cells - 1) / cells), int(((i+1)*p + cells - 1) / cells)
+}
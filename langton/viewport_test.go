@@ -0,0 +1,123 @@
+package langton
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+)
+
+func TestViewportScaleUp(t *testing.T) {
+	ant := NewAntFromString(NewBoard(10), "LLRR")
+	ant.NextN(300)
+	palette := ToPalette(GradientPalette(len(ant.steps)))
+
+	want := ToRGBA(ant, palette, 3)
+	dimensions := ant.Dimensions()
+	viewport := Viewport{
+		World:  dimensions,
+		Width:  int(dimensions.Width()) * 3,
+		Height: int(dimensions.Height()) * 3,
+	}
+	got, err := viewport.Render(ant, palette)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got.Bounds() != want.Bounds() {
+		t.Fatalf("Render() bounds = %v, want %v", got.Bounds(), want.Bounds())
+	}
+	for y := 0; y < want.Bounds().Dy(); y++ {
+		for x := 0; x < want.Bounds().Dx(); x++ {
+			if got.At(x, y) != want.At(x, y) {
+				t.Fatalf("Render() at (%d, %d) = %v, want %v", x, y, got.At(x, y), want.At(x, y))
+			}
+		}
+	}
+}
+
+func TestViewportScaleDown(t *testing.T) {
+	steps := StepsFromString("LRR")
+	steps.Numerate()
+	palette := color.Palette{
+		color.RGBA{},
+		color.RGBA{R: 255, A: 255},
+		color.RGBA{G: 255, A: 255},
+		color.RGBA{B: 255, A: 255},
+	}
+	board := NewSparseBoard(NewBoard(100))
+	// the world starts far from the origin, the block of 2x2 cells at its corner has 3 visited cells
+	// and the next block has a single one
+	board.SetCell(Cell{Point: Point{X: 50, Y: 60}, Step: steps[1]})
+	board.SetCell(Cell{Point: Point{X: 51, Y: 60}, Step: steps[1]})
+	board.SetCell(Cell{Point: Point{X: 50, Y: 61}, Step: steps[2]})
+	board.SetCell(Cell{Point: Point{X: 53, Y: 61}, Step: steps[2]})
+	ant := NewAntOnBoard(board, steps...)
+	ant.Position.Point = Point{X: -50, Y: -50}
+
+	tests := []struct {
+		reduction Reduction
+		first     color.Color
+		second    color.Color
+	}{
+		{
+			reduction: ReduceMajority,
+			first:     palette[2],
+			second:    palette[0],
+		},
+		{
+			reduction: ReduceAverage,
+			first:     color.RGBA64{G: 0xffff / 2, B: 0xffff / 4, A: 0xffff * 3 / 4},
+			second:    color.RGBA64{B: 0xffff / 4, A: 0xffff / 4},
+		},
+		{
+			reduction: ReduceAnyVisited,
+			first:     palette[2],
+			second:    palette[3],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reduction.String(), func(t *testing.T) {
+			viewport := Viewport{
+				World:     NewDimensions(50, 60, 57, 67),
+				Width:     4,
+				Height:    4,
+				Reduction: tt.reduction,
+			}
+			img, err := viewport.Render(ant, palette)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got, want := img.At(0, 0), color.RGBAModel.Convert(tt.first); got != want {
+				t.Errorf("Render() at (0, 0) = %v, want %v", got, want)
+			}
+			if got, want := img.At(1, 0), color.RGBAModel.Convert(tt.second); got != want {
+				t.Errorf("Render() at (1, 0) = %v, want %v", got, want)
+			}
+			if got, want := img.At(3, 3), color.RGBAModel.Convert(palette[0]); got != want {
+				t.Errorf("Render() at (3, 3) = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFitViewport(t *testing.T) {
+	viewport := FitViewport(NewDimensions(0, 0, 9999, 4999), 1000, 1000, ReduceAverage)
+	if viewport.Width != 1000 || viewport.Height != 500 {
+		t.Errorf("FitViewport() = %dx%d, want 1000x500", viewport.Width, viewport.Height)
+	}
+	_, err := Viewport{World: NewBoard(10)}.Render(NewAntFromString(NewBoard(10), "LR"), color.Palette{})
+	if !errors.Is(err, ErrInvalidViewport) {
+		t.Errorf("Render() error = %v, want %v", err, ErrInvalidViewport)
+	}
+}
+
+func TestParseReduction(t *testing.T) {
+	for reduction := ReduceMajority; reduction < ReduceInvalid; reduction++ {
+		got, err := ParseReduction(reduction.String())
+		if err != nil || got != reduction {
+			t.Errorf("ParseReduction(%s) = %v, %v", reduction, got, err)
+		}
+	}
+	if _, err := ParseReduction("median"); !errors.Is(err, ErrInvalidViewport) {
+		t.Errorf("ParseReduction() error = %v, want %v", err, ErrInvalidViewport)
+	}
+}
@@ -0,0 +1,48 @@
+package patterns
+
+import "flag"
+
+// Selection holds the -patterns, -pattern and -tag flags that the commands use to pick rules from a library
+type Selection struct {
+	// Path is the library file
+	Path string
+	// Pattern is a rule or the name of a rule
+	Pattern string
+	// Tag selects every rule with the tag
+	Tag string
+}
+
+// Flags registers the -patterns, -pattern and -tag flags in the flag set, use flag.CommandLine for the default one
+func Flags(fs *flag.FlagSet) *Selection {
+	selection := &Selection{}
+	fs.StringVar(&selection.Path, "patterns", "patterns.txt", "library of rules used by pattern and tag")
+	fs.StringVar(&selection.Pattern, "pattern", "", "rule or name of a rule in the patterns library, overrides the rule. e.g. \"The tree!\"")
+	fs.StringVar(&selection.Tag, "tag", "", "rules with this tag in the patterns library, the first one if a single rule is run, overrides the rule. e.g. highway")
+	return selection
+}
+
+// Selected returns true if a pattern or a tag has been given
+func (selection *Selection) Selected() bool {
+	return selection.Pattern != "" || selection.Tag != ""
+}
+
+// Entries loads the library and returns the entries selected by the pattern or the tag
+func (selection *Selection) Entries() ([]Entry, error) {
+	library, err := Load(selection.Path)
+	if err != nil {
+		return nil, err
+	}
+	return library.Select(selection.Pattern, selection.Tag)
+}
+
+// Rule returns the rule of the first entry selected, or steps if neither a pattern nor a tag have been given
+func (selection *Selection) Rule(steps string) (string, error) {
+	if !selection.Selected() {
+		return steps, nil
+	}
+	entries, err := selection.Entries()
+	if err != nil {
+		return "", err
+	}
+	return entries[0].Rule, nil
+}
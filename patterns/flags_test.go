@@ -0,0 +1,31 @@
+package patterns
+
+import (
+	"errors"
+	"flag"
+	"testing"
+)
+
+func TestFlags(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+		err  error
+	}{
+		{nil, "LR", nil},
+		{[]string{"-pattern", "The tree!"}, "LLRRLLLRLRRR", nil},
+		{[]string{"-tag", "highway"}, "LLLLLLRRLRLL", nil},
+		{[]string{"-tag", "nothing"}, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		selection := Flags(fs)
+		if err := fs.Parse(append([]string{"-patterns", "../patterns.txt"}, tt.args...)); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		got, err := selection.Rule("LR")
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Selection.Rule() with %v = %v, %v, want %v, %v", tt.args, got, err, tt.want, tt.err)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+// Package patterns reads and writes libraries of curated rules in the format of patterns.txt.
+// Each line holds a rule followed by an optional comment after #, a list of comma separated labels.
+// Short labels like "highway" or "not uniform" are tags, longer ones like "The tree!" are notes that name the rule.
+// Blank lines and lines with only a comment group the rules and are written back untouched.
+package patterns
+
+import (
+	"errors"
+	"fmt"
+	"go-ant/langton"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+var (
+	ErrInvalidPattern = errors.New("Invalid pattern")
+	ErrNotFound       = errors.New("Pattern not found")
+)
+
+// Entry is a rule of the library with its labels, labels cannot contain commas or #
+type Entry struct {
+	Rule  string
+	Tags  []string
+	Notes []string
+
+	// line is the text of the entry as it was read, it is kept while the entry does not change
+	line string
+}
+
+// Steps parses the rule of the entry
+func (entry Entry) Steps() (langton.Steps, error) {
+	return langton.ParseSteps(entry.Rule)
+}
+
+// HasTag returns true if the entry has the tag, tags are compared without case and plural,
+// so "triangle" matches "Triangles"
+func (entry Entry) HasTag(tag string) bool {
+	tag = normalizeTag(tag)
+	for _, t := range entry.Tags {
+		if normalizeTag(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNote returns true if one of the notes is the given text, ignoring the case
+func (entry Entry) HasNote(note string) bool {
+	for _, n := range entry.Notes {
+		if strings.EqualFold(n, note) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the entry as a line of the library.
+// The line is written as it was read unless the rule or the labels have changed
+func (entry Entry) String() string {
+	if entry.unchanged() {
+		return entry.line
+	}
+	comment := strings.Join(append(append([]string{}, entry.Tags...), entry.Notes...), ", ")
+	if comment == "" {
+		return entry.Rule
+	}
+	return entry.Rule + " # " + comment
+}
+
+// unchanged returns true if the line that was read has the same rule, tags and notes as the entry
+func (entry Entry) unchanged() bool {
+	if entry.line == "" {
+		return false
+	}
+	read := parseEntry(entry.line)
+	return read.Rule == entry.Rule && equal(read.Tags, entry.Tags) && equal(read.Notes, entry.Notes)
+}
+
+// Library is a list of entries that keeps the blank lines and comments of its file
+type Library struct {
+	entries []Entry
+	// lines holds the text of the lines without an entry, and an empty string for the lines with one
+	lines []string
+	// index is the position in entries of each line, -1 for the lines without an entry
+	index []int
+	// unterminated is true if the last line did not end with a new line
+	unterminated bool
+}
+
+// New creates an empty library
+func New() *Library {
+	return &Library{}
+}
+
+// Parse reads a library, every rule must be a valid sequence of steps
+func Parse(r io.Reader) (*Library, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	library := New()
+	lines := strings.Split(string(data), "\n")
+	unterminated := lines[len(lines)-1] != ""
+	if !unterminated {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			library.lines = append(library.lines, line)
+			library.index = append(library.index, -1)
+			continue
+		}
+		entry, err := ParseEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		library.Add(entry)
+	}
+	library.unterminated = unterminated
+	return library, nil
+}
+
+// ParseEntry reads a single line with a rule and its comment
+func ParseEntry(line string) (Entry, error) {
+	entry := parseEntry(line)
+	if entry.Rule == "" {
+		return entry, fmt.Errorf("%w: missing rule in %q", ErrInvalidPattern, line)
+	}
+	_, err := entry.Steps()
+	if err != nil {
+		return entry, fmt.Errorf("%w: %s", ErrInvalidPattern, err)
+	}
+	return entry, nil
+}
+
+func parseEntry(line string) Entry {
+	parts := strings.SplitN(line, "#", 2)
+	entry := Entry{
+		Rule: strings.TrimSpace(parts[0]),
+		line: line,
+	}
+	if len(parts) == 2 {
+		entry.Tags, entry.Notes = parseLabels(parts[1])
+	}
+	return entry
+}
+
+// Load reads the library in the file
+func Load(path string) (*Library, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Save writes the library to the file
+func (library *Library) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = library.WriteTo(file)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteTo writes the library, a library that has not been modified is written exactly as it was read
+func (library *Library) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for i, line := range library.lines {
+		if library.index[i] >= 0 {
+			line = library.entries[library.index[i]].String()
+		}
+		if i < len(library.lines)-1 || !library.unterminated {
+			line += "\n"
+		}
+		n, err := io.WriteString(w, line)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Entries returns all the entries in the order of the file
+func (library *Library) Entries() []Entry {
+	return append([]Entry{}, library.entries...)
+}
+
+// Add appends the entry at the end of the library
+func (library *Library) Add(entry Entry) {
+	library.unterminated = false
+	library.lines = append(library.lines, "")
+	library.index = append(library.index, len(library.entries))
+	library.entries = append(library.entries, entry)
+}
+
+// Update replaces the entry with the same rule, it returns ErrNotFound if there is none
+func (library *Library) Update(entry Entry) error {
+	for i := range library.entries {
+		if library.entries[i].Rule == entry.Rule {
+			// the original line is kept to be written if nothing has changed
+			if entry.line == "" {
+				entry.line = library.entries[i].line
+			}
+			library.entries[i] = entry
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotFound, entry.Rule)
+}
+
+// Lookup returns the entry with the rule or with the note given by name
+func (library *Library) Lookup(name string) (Entry, error) {
+	for _, entry := range library.entries {
+		if entry.Rule == name {
+			return entry, nil
+		}
+	}
+	for _, entry := range library.entries {
+		if entry.HasNote(name) {
+			return entry, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// WithTag returns the entries with the tag in the order of the file
+func (library *Library) WithTag(tag string) []Entry {
+	out := []Entry{}
+	for _, entry := range library.entries {
+		if entry.HasTag(tag) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// Tags returns every tag used in the library once, with the spelling of its first use
+func (library *Library) Tags() []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, entry := range library.entries {
+		for _, tag := range entry.Tags {
+			if seen[normalizeTag(tag)] {
+				continue
+			}
+			seen[normalizeTag(tag)] = true
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// Select returns the entry named by pattern if it is not empty, otherwise the entries with the tag
+func (library *Library) Select(pattern string, tag string) ([]Entry, error) {
+	if pattern != "" {
+		entry, err := library.Lookup(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return []Entry{entry}, nil
+	}
+	entries := library.WithTag(tag)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no rule with tag %s", ErrNotFound, tag)
+	}
+	return entries, nil
+}
+
+// maxTagWords is the longest label, in words, that is taken as a tag
+const maxTagWords = 2
+
+// parseLabels splits a comment in tags and notes.
+// Labels with more than maxTagWords words or with punctuation other than hyphens are notes
+func parseLabels(comment string) (tags []string, notes []string) {
+	for _, label := range strings.Split(comment, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		if isTag(label) {
+			tags = append(tags, label)
+		} else {
+			notes = append(notes, label)
+		}
+	}
+	return tags, notes
+}
+
+func isTag(label string) bool {
+	if len(strings.Fields(label)) > maxTagWords {
+		return false
+	}
+	for _, c := range label {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == ' ') {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTag removes the case and the plural of a tag
+func normalizeTag(tag string) string {
+	tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+	if len(tag) > 3 && strings.HasSuffix(tag, "s") && !strings.HasSuffix(tag, "ss") {
+		tag = tag[:len(tag)-1]
+	}
+	return tag
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package patterns
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	data, err := ioutil.ReadFile("../patterns.txt")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	library, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	buf := &bytes.Buffer{}
+	_, err = library.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if buf.String() != string(data) {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), string(data))
+	}
+}
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		line  string
+		rule  string
+		tags  []string
+		notes []string
+	}{
+		{
+			line: "LLRRLLLRLRRR # highway, Linear, The tree!",
+			rule: "LLRRLLLRLRRR",
+			tags: []string{"highway", "Linear"}, notes: []string{"The tree!"},
+		},
+		{
+			line: "LLRRLRLRLLL # Slow chainsaw grow, end stable",
+			rule: "LLRRLRLRLLL",
+			tags: []string{"end stable"}, notes: []string{"Slow chainsaw grow"},
+		},
+		{
+			line: "RLLLLRRRLLL",
+			rule: "RLLLLRRRLLL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			got, err := ParseEntry(tt.line)
+			if err != nil {
+				t.Fatalf("ParseEntry() error = %v", err)
+			}
+			if got.Rule != tt.rule || !equal(got.Tags, tt.tags) || !equal(got.Notes, tt.notes) {
+				t.Errorf("ParseEntry() = %+v, want %s %v %v", got, tt.rule, tt.tags, tt.notes)
+			}
+			if got.String() != tt.line {
+				t.Errorf("String() = %q, want %q", got.String(), tt.line)
+			}
+		})
+	}
+
+	_, err := ParseEntry("LRX # wrong")
+	if !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("ParseEntry() error = %v, want %v", err, ErrInvalidPattern)
+	}
+}
+
+func TestLibraryQueries(t *testing.T) {
+	library, err := Load("../patterns.txt")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	entry, err := library.Lookup("the tree!")
+	if err != nil || entry.Rule != "LLRRLLLRLRRR" {
+		t.Errorf("Lookup() = %v, %v, want LLRRLLLRLRRR", entry, err)
+	}
+	_, err = library.Lookup("LLLLLLLLLLLL")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() error = %v, want %v", err, ErrNotFound)
+	}
+	if got := library.WithTag("Linear"); len(got) != 5 {
+		t.Errorf("WithTag(Linear) = %v, want 5 entries", got)
+	}
+	// case and plural are ignored
+	if got := library.WithTag("triangle"); len(got) != 8 {
+		t.Errorf("WithTag(triangle) = %v, want 8 entries", got)
+	}
+	entries, err := library.Select("", "symmetric")
+	if err != nil || len(entries) != 3 {
+		t.Errorf("Select() = %v, %v, want 3 entries", entries, err)
+	}
+}
+
+func TestLibraryUpdate(t *testing.T) {
+	library, err := Parse(strings.NewReader("LR  #highway\n\n# group\nRL\n"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	entry, _ := library.Lookup("RL")
+	entry.Tags = append(entry.Tags, "highway")
+	err = library.Update(entry)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	library.Add(Entry{Rule: "LLRR", Tags: []string{"Symmetric"}, Notes: []string{"The square of LR"}})
+
+	buf := &bytes.Buffer{}
+	library.WriteTo(buf)
+	want := "LR  #highway\n\n# group\nRL # highway\nLLRR # Symmetric, The square of LR\n"
+	if buf.String() != want {
+		t.Errorf("WriteTo() = %q, want %q", buf.String(), want)
+	}
+	if err := library.Update(Entry{Rule: "LLL"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update() error = %v, want %v", err, ErrNotFound)
+	}
+}